@@ -2,6 +2,7 @@ package astutil
 
 import (
 	"go/ast"
+	"regexp"
 	"strings"
 )
 
@@ -25,3 +26,243 @@ func ParseComment(comment *ast.CommentGroup) string {
 	}
 	return b.String()
 }
+
+// Doc 结构化的文档
+type Doc struct {
+	// Summary 摘要，第一个段落的第一句话
+	Summary string `json:"summary" yaml:"summary"`
+
+	// Paragraphs 段落，段落内的多行使用空格连接，不包括代码块、指令和注解
+	Paragraphs []string `json:"paragraphs" yaml:"paragraphs"`
+
+	// CodeBlocks 代码块，即缩进的行，已去掉公共的缩进
+	CodeBlocks []string `json:"code_blocks" yaml:"codeBlocks"`
+
+	// Deprecated 废弃说明，即以 "Deprecated: " 开头的段落
+	Deprecated string `json:"deprecated" yaml:"deprecated"`
+
+	// Directives 指令，例如 //go:generate stringer、//nolint:errcheck
+	Directives []*Directive `json:"directives" yaml:"directives"`
+
+	// Annotations 注解，例如 @route /users
+	Annotations []*Annotation `json:"annotations" yaml:"annotations"`
+}
+
+// Directive 指令，例如 //go:generate stringer -type=Kind、//line a.go:10、//export Foo
+type Directive struct {
+	// Name 指令名，例如 go:generate、nolint:errcheck
+	Name string `json:"name" yaml:"name"`
+
+	// Args 指令参数，例如 stringer -type=Kind
+	Args string `json:"args" yaml:"args"`
+}
+
+// Annotation 注解，例如 @route /users 的 Key 为 route，Value 为 /users
+type Annotation struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// Annotation 查找第一个对应key的注解
+func (d *Doc) Annotation(key string) (string, bool) {
+	if d == nil {
+		return "", false
+	}
+	for _, annotation := range d.Annotations {
+		if annotation.Key == key {
+			return annotation.Value, true
+		}
+	}
+	return "", false
+}
+
+// Directive 查找第一个对应名称的指令
+func (d *Doc) Directive(name string) (*Directive, bool) {
+	if d == nil {
+		return nil, false
+	}
+	for _, directive := range d.Directives {
+		if directive.Name == name {
+			return directive, true
+		}
+	}
+	return nil, false
+}
+
+// ParseDoc 解析文档，生成结构化的文档，comment为nil时返回nil
+func ParseDoc(comment *ast.CommentGroup) *Doc {
+	if comment == nil {
+		return nil
+	}
+	doc := &Doc{}
+	var paragraph []string
+	var code []string
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		text := strings.Join(paragraph, " ")
+		doc.Paragraphs = append(doc.Paragraphs, text)
+		if strings.HasPrefix(text, "Deprecated: ") {
+			doc.Deprecated = strings.TrimPrefix(text, "Deprecated: ")
+		}
+		paragraph = nil
+	}
+	flushCode := func() {
+		// 去掉代码块结尾的空行
+		for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+			code = code[:len(code)-1]
+		}
+		if len(code) > 0 {
+			doc.CodeBlocks = append(doc.CodeBlocks, dedent(code))
+		}
+		code = nil
+	}
+
+	for _, c := range comment.List {
+		if directive, ok := parseDirective(c.Text); ok {
+			doc.Directives = append(doc.Directives, directive)
+			continue
+		}
+		for _, line := range commentLines(c.Text) {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case trimmed == "":
+				flushParagraph()
+				if len(code) > 0 {
+					code = append(code, "")
+				}
+			case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+				flushParagraph()
+				code = append(code, line)
+			case strings.HasPrefix(trimmed, "@"):
+				flushParagraph()
+				flushCode()
+				doc.Annotations = append(doc.Annotations, parseAnnotation(trimmed))
+			default:
+				flushCode()
+				paragraph = append(paragraph, trimmed)
+			}
+		}
+	}
+	flushParagraph()
+	flushCode()
+
+	if len(doc.Paragraphs) > 0 {
+		doc.Summary = summary(doc.Paragraphs[0])
+	}
+	return doc
+}
+
+// FindAnnotations 查找文档中以前缀开头的行，返回去掉注释符号和首尾空格的行，例如 @compose name=pay
+func FindAnnotations(comment *ast.CommentGroup, prefixes ...string) []string {
+	if comment == nil || len(prefixes) == 0 {
+		return nil
+	}
+	var annotations []string
+	for _, c := range comment.List {
+		for _, line := range commentLines(c.Text) {
+			line = strings.TrimSpace(line)
+			for _, prefix := range prefixes {
+				if prefix != "" && strings.HasPrefix(line, prefix) {
+					annotations = append(annotations, line)
+					break
+				}
+			}
+		}
+	}
+	return annotations
+}
+
+// directiveRe 形如 //go:generate、//nolint:errcheck 的指令
+var directiveRe = regexp.MustCompile(`^[a-z0-9]+:\S`)
+
+// namedDirectives 没有冒号的指令
+var namedDirectives = []string{"line", "export", "extern", "nolint"}
+
+// parseDirective 解析指令，指令为 // 后面紧跟 名称:xxx 的注释，或者 //line、//export、//extern、//nolint，
+// 其他 // 后面没有空格的注释，例如 //todo fix later，不是指令
+func parseDirective(text string) (*Directive, bool) {
+	if !strings.HasPrefix(text, "//") {
+		return nil, false
+	}
+	text = strings.TrimPrefix(text, "//")
+	directive := &Directive{Name: text}
+	if index := strings.IndexAny(text, " \t"); index >= 0 {
+		directive.Name = text[:index]
+		directive.Args = strings.TrimSpace(text[index+1:])
+	}
+	if directiveRe.MatchString(text) {
+		return directive, true
+	}
+	for _, name := range namedDirectives {
+		if directive.Name == name {
+			return directive, true
+		}
+	}
+	return nil, false
+}
+
+// commentLines 去掉注释符号后的行，保留 // 后第一个空格之后的缩进
+func commentLines(text string) []string {
+	if strings.HasPrefix(text, "//") {
+		text = strings.TrimPrefix(text, "//")
+		return []string{strings.TrimPrefix(text, " ")}
+	}
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(strings.TrimRight(line, " \t"), " ")
+	}
+	return lines
+}
+
+// parseAnnotation 解析注解，例如 @route /users
+func parseAnnotation(text string) *Annotation {
+	text = strings.TrimPrefix(text, "@")
+	annotation := &Annotation{Key: text}
+	if index := strings.IndexAny(text, " \t"); index >= 0 {
+		annotation.Key = text[:index]
+		annotation.Value = strings.TrimSpace(text[index+1:])
+	}
+	return annotation
+}
+
+// summary 段落的第一句话
+func summary(paragraph string) string {
+	for i, r := range paragraph {
+		switch r {
+		case '。', '！', '？':
+			return paragraph[:i+len(string(r))]
+		case '.', '!', '?':
+			if i+1 == len(paragraph) || paragraph[i+1] == ' ' {
+				return paragraph[:i+1]
+			}
+		}
+	}
+	return paragraph
+}
+
+// dedent 去掉代码块公共的缩进
+func dedent(lines []string) string {
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix = indent
+			first = false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, prefix)
+	}
+	return strings.Join(lines, "\n")
+}
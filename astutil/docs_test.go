@@ -2,6 +2,7 @@ package astutil
 
 import (
 	"go/ast"
+	"reflect"
 	"testing"
 )
 
@@ -41,3 +42,90 @@ func TestParseComment(t *testing.T) {
 		)
 	}
 }
+
+func TestParseDoc(t *testing.T) {
+	comments := func(texts ...string) *ast.CommentGroup {
+		group := &ast.CommentGroup{}
+		for _, text := range texts {
+			group.List = append(group.List, &ast.Comment{Text: text})
+		}
+		return group
+	}
+	tests := []struct {
+		name    string
+		comment *ast.CommentGroup
+		want    *Doc
+	}{
+		{
+			name:    "nil",
+			comment: nil,
+			want:    nil,
+		},
+		{
+			name: "paragraphs",
+			comment: comments(
+				"// Foo does something. And more.", "// second line", "//", "// Deprecated: use Bar.",
+			),
+			want: &Doc{
+				Summary:    "Foo does something.",
+				Paragraphs: []string{"Foo does something. And more. second line", "Deprecated: use Bar."},
+				Deprecated: "use Bar.",
+			},
+		},
+		{
+			name:    "chinese",
+			comment: comments("// Foo 解析文档。生成可阅读的格式"),
+			want: &Doc{
+				Summary:    "Foo 解析文档。",
+				Paragraphs: []string{"Foo 解析文档。生成可阅读的格式"},
+			},
+		},
+		{
+			name: "code and annotations",
+			comment: comments(
+				"// Foo example", "//", "//	a := 1", "//	if a > 0 {", "//		a++", "//	}", "//",
+				"// @route /foo", "// @auth", "//go:generate stringer -type=Foo",
+			),
+			want: &Doc{
+				Summary:     "Foo example",
+				Paragraphs:  []string{"Foo example"},
+				CodeBlocks:  []string{"a := 1\nif a > 0 {\n\ta++\n}"},
+				Directives:  []*Directive{{Name: "go:generate", Args: "stringer -type=Foo"}},
+				Annotations: []*Annotation{{Key: "route", Value: "/foo"}, {Key: "auth"}},
+			},
+		},
+		{
+			name: "directives",
+			comment: comments(
+				"// Foo directives.", "//todo fix later", "//nolint", "//line foo.go:10", "//export Foo",
+				"//nolint:errcheck",
+			),
+			want: &Doc{
+				Summary:    "Foo directives.",
+				Paragraphs: []string{"Foo directives. todo fix later"},
+				Directives: []*Directive{
+					{Name: "nolint"}, {Name: "line", Args: "foo.go:10"}, {Name: "export", Args: "Foo"},
+					{Name: "nolint:errcheck"},
+				},
+			},
+		},
+		{
+			name:    "block",
+			comment: comments("/*\nFoo block comment\n\n@key value\n*/"),
+			want: &Doc{
+				Summary:     "Foo block comment",
+				Paragraphs:  []string{"Foo block comment"},
+				Annotations: []*Annotation{{Key: "key", Value: "value"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if got := ParseDoc(tt.comment); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ParseDoc() = %#v, want %#v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,199 @@
+package astutil
+
+import (
+	"bytes"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"strings"
+)
+
+// ExprKind 表达式的类型
+type ExprKind string
+
+const (
+	// ExprIdent 标识符，例如 nil、true、Foo
+	ExprIdent ExprKind = "ident"
+	// ExprBasicLit 基础类型字面量，例如 1、"a"、'c'
+	ExprBasicLit ExprKind = "basic_lit"
+	// ExprCompositeLit 复合字面量，例如 []int{1, 2}、T{A: 1}
+	ExprCompositeLit ExprKind = "composite_lit"
+	// ExprFuncLit 函数字面量
+	ExprFuncLit ExprKind = "func_lit"
+	// ExprKeyValue 复合字面量中的键值对，例如 A: 1
+	ExprKeyValue ExprKind = "key_value"
+	// ExprUnary 一元表达式，例如 -1、&T{}、<-ch
+	ExprUnary ExprKind = "unary"
+	// ExprBinary 二元表达式，例如 1 << 10
+	ExprBinary ExprKind = "binary"
+	// ExprCall 函数调用或者类型转换，例如 errors.New("a")、int64(1)
+	ExprCall ExprKind = "call"
+	// ExprSelector 选择器，例如 time.Second
+	ExprSelector ExprKind = "selector"
+	// ExprIndex 索引表达式，例如 a[0]、List[int]
+	ExprIndex ExprKind = "index"
+	// ExprSlice 切片表达式，例如 a[1:2]
+	ExprSlice ExprKind = "slice"
+	// ExprTypeAssert 类型断言，例如 v.(string)
+	ExprTypeAssert ExprKind = "type_assert"
+	// ExprParen 括号表达式，例如 (a + b)
+	ExprParen ExprKind = "paren"
+	// ExprStar 指针类型或者解引用，例如 *T、*p
+	ExprStar ExprKind = "star"
+	// ExprType 类型表达式，例如 []int、map[string]int、func()
+	ExprType ExprKind = "type"
+)
+
+// ExprNode 表达式的树形结构
+type ExprNode struct {
+	// Kind 表达式的类型
+	Kind ExprKind `json:"kind" yaml:"kind"`
+
+	// Code 表达式的代码
+	Code string `json:"code" yaml:"code"`
+
+	// Op 一元和二元表达式的操作符，例如 -、<<
+	Op string `json:"op,omitempty" yaml:"op,omitempty"`
+
+	// Value 标识符的名称、字面量的值或者选择器的字段名
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// Type 复合字面量、函数字面量、类型断言的类型
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Children 子表达式，例如二元表达式的左右两边、函数调用的函数和参数、复合字面量的元素、
+	// 索引表达式的对象和索引，切片表达式省略的下标为nil
+	Children []*ExprNode `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// ExprString 使用 go/printer 生成表达式的代码，去掉原来的换行和注释，例如 []int{1, 2}
+func ExprString(expr ast.Node) (string, error) {
+	buf := &bytes.Buffer{}
+	// 使用新的 FileSet 丢弃原来的位置信息，表达式会打印在同一行（函数体除外）
+	if err := printer.Fprint(buf, token.NewFileSet(), expr); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ParseExprTree 解析表达式的树形结构
+func ParseExprTree(expr ast.Expr) (*ExprNode, error) {
+	if expr == nil {
+		return nil, nil
+	}
+	code, err := ExprString(expr)
+	if err != nil {
+		return nil, err
+	}
+	node := &ExprNode{Code: code}
+	var children []ast.Expr
+	switch tp := expr.(type) {
+	case *ast.Ident:
+		node.Kind = ExprIdent
+		node.Value = tp.Name
+	case *ast.BasicLit:
+		node.Kind = ExprBasicLit
+		node.Value = tp.Value
+	case *ast.CompositeLit:
+		node.Kind = ExprCompositeLit
+		if tp.Type != nil {
+			node.Type, err = ExprString(tp.Type)
+			if err != nil {
+				return nil, err
+			}
+		}
+		children = tp.Elts
+	case *ast.FuncLit:
+		node.Kind = ExprFuncLit
+		node.Type, err = ExprString(tp.Type)
+		if err != nil {
+			return nil, err
+		}
+	case *ast.KeyValueExpr:
+		node.Kind = ExprKeyValue
+		children = []ast.Expr{tp.Key, tp.Value}
+	case *ast.UnaryExpr:
+		node.Kind = ExprUnary
+		node.Op = tp.Op.String()
+		children = []ast.Expr{tp.X}
+	case *ast.BinaryExpr:
+		node.Kind = ExprBinary
+		node.Op = tp.Op.String()
+		children = []ast.Expr{tp.X, tp.Y}
+	case *ast.CallExpr:
+		node.Kind = ExprCall
+		children = append([]ast.Expr{tp.Fun}, tp.Args...)
+	case *ast.SelectorExpr:
+		node.Kind = ExprSelector
+		node.Value = tp.Sel.Name
+		children = []ast.Expr{tp.X}
+	case *ast.IndexExpr:
+		node.Kind = ExprIndex
+		children = []ast.Expr{tp.X, tp.Index}
+	case *ast.IndexListExpr:
+		node.Kind = ExprIndex
+		children = append([]ast.Expr{tp.X}, tp.Indices...)
+	case *ast.SliceExpr:
+		node.Kind = ExprSlice
+		children = []ast.Expr{tp.X, tp.Low, tp.High, tp.Max}
+		if !tp.Slice3 {
+			children = children[:3]
+		}
+	case *ast.TypeAssertExpr:
+		node.Kind = ExprTypeAssert
+		if tp.Type != nil {
+			// x.(type) 的类型为nil
+			node.Type, err = ExprString(tp.Type)
+			if err != nil {
+				return nil, err
+			}
+		}
+		children = []ast.Expr{tp.X}
+	case *ast.ParenExpr:
+		node.Kind = ExprParen
+		children = []ast.Expr{tp.X}
+	case *ast.StarExpr:
+		node.Kind = ExprStar
+		children = []ast.Expr{tp.X}
+	case *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.StructType,
+		*ast.InterfaceType, *ast.Ellipsis:
+		node.Kind = ExprType
+		node.Type = code
+	default:
+		return nil, NewUnsupportedTypeError(expr)
+	}
+	for _, child := range children {
+		childNode, err := ParseExprTree(child)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, childNode)
+	}
+	return node, nil
+}
+
+// String 打印表达式的树形结构，每个节点一行
+func (n *ExprNode) String() string {
+	sb := &strings.Builder{}
+	n.print(sb, 0)
+	return sb.String()
+}
+
+func (n *ExprNode) print(sb *strings.Builder, depth int) {
+	sb.WriteString(strings.Repeat("  ", depth))
+	if n == nil {
+		sb.WriteString("<nil>\n")
+		return
+	}
+	sb.WriteString(string(n.Kind))
+	for _, attr := range []string{n.Op, n.Value, n.Type} {
+		if attr != "" {
+			sb.WriteString(" ")
+			sb.WriteString(attr)
+		}
+	}
+	sb.WriteString("\n")
+	for _, child := range n.Children {
+		child.print(sb, depth+1)
+	}
+}
@@ -0,0 +1,94 @@
+package astutil
+
+import (
+	"go/parser"
+	"testing"
+)
+
+func TestExprString(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{
+			name: "composite",
+			expr: "map[string]*T{\n\t\"a\": {A: 1, B: []int{1,\n 2}}, // comment\n}",
+			want: `map[string]*T{"a": {A: 1, B: []int{1, 2}}}`,
+		},
+		{
+			name: "binary",
+			expr: "time.Second*2 + (a<<1)",
+			want: "time.Second*2 + (a << 1)",
+		},
+		{
+			name: "index and slice",
+			expr: "a[1][2:3:4]",
+			want: "a[1][2:3:4]",
+		},
+		{
+			name: "type assert",
+			expr: "v.(fmt.Stringer)",
+			want: "v.(fmt.Stringer)",
+		},
+		{
+			name: "call",
+			expr: `errors.New("a")`,
+			want: `errors.New("a")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				expr, err := parser.ParseExpr(tt.expr)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				got, err := ExprString(expr)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if got != tt.want {
+					t.Errorf("ExprString() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestParseExprTree(t *testing.T) {
+	expr, err := parser.ParseExpr(`&Config{Name: "a", Ports: []int{80, 443}[:1], Timeout: 2 * time.Second}`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tree, err := ParseExprTree(expr)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	want := `unary &
+  composite_lit Config
+    key_value
+      ident Name
+      basic_lit "a"
+    key_value
+      ident Ports
+      slice
+        composite_lit []int
+          basic_lit 80
+          basic_lit 443
+        <nil>
+        basic_lit 1
+    key_value
+      ident Timeout
+      binary *
+        basic_lit 2
+        selector Second
+          ident time
+`
+	if got := tree.String(); got != want {
+		t.Errorf("ParseExprTree() =\n%v\nwant\n%v", got, want)
+	}
+	if tree.Children[0].Code != `Config{Name: "a", Ports: []int{80, 443}[:1], Timeout: 2 * time.Second}` {
+		t.Errorf("code = %v", tree.Children[0].Code)
+	}
+}
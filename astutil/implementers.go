@@ -0,0 +1,138 @@
+package astutil
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"log"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Implementer 接口的实现类型
+type Implementer struct {
+	// PkgPath 类型所在包的导入路径
+	PkgPath string `json:"pkg_path" yaml:"pkgPath"`
+
+	// Name 类型名
+	Name string `json:"name" yaml:"name"`
+
+	// Pointer 为true时只有 *T 实现了接口，否则 T 和 *T 都实现了接口
+	Pointer bool `json:"pointer" yaml:"pointer"`
+
+	// Position 类型定义的位置
+	Position token.Position `json:"position" yaml:"position"`
+}
+
+// String 打印，例如 *github.com/pjoc-team/ast/astutil.Parser
+func (i *Implementer) String() string {
+	if i.Pointer {
+		return "*" + i.PkgPath + "." + i.Name
+	}
+	return i.PkgPath + "." + i.Name
+}
+
+// FindImplementers 在包中查找实现了接口的类型。
+// interfaceName 可以是接口名，也可以带上包名或者导入路径，例如 Reader、io.Reader
+func FindImplementers(pkgs []*packages.Package, interfaceName string) ([]*Implementer, error) {
+	checkTypes(pkgs)
+	iface, err := lookupInterface(pkgs, interfaceName)
+	if err != nil {
+		return nil, err
+	}
+	return FindImplementersOf(pkgs, iface)
+}
+
+// FindImplementersOf 在包中查找实现了接口的类型，不包括接口类型
+func FindImplementersOf(pkgs []*packages.Package, iface *types.Interface) ([]*Implementer, error) {
+	checkTypes(pkgs)
+	implementers := make([]*Implementer, 0)
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			return nil, fmt.Errorf("no type info of package: %v", pkg.ID)
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || obj.IsAlias() || types.IsInterface(obj.Type()) {
+				continue
+			}
+			var pointer bool
+			if types.Implements(obj.Type(), iface) {
+				pointer = false
+			} else if types.Implements(types.NewPointer(obj.Type()), iface) {
+				pointer = true
+			} else {
+				continue
+			}
+			implementer := &Implementer{
+				PkgPath: pkg.PkgPath,
+				Name:    obj.Name(),
+				Pointer: pointer,
+			}
+			if pkg.Fset != nil {
+				implementer.Position = pkg.Fset.Position(obj.Pos())
+			}
+			implementers = append(implementers, implementer)
+		}
+	}
+	sort.Slice(
+		implementers, func(i, j int) bool {
+			if implementers[i].PkgPath != implementers[j].PkgPath {
+				return implementers[i].PkgPath < implementers[j].PkgPath
+			}
+			return implementers[i].Name < implementers[j].Name
+		},
+	)
+	return implementers, nil
+}
+
+func checkTypes(pkgs []*packages.Package) {
+	for _, pkg := range pkgs {
+		if err := CheckTypes(pkg); err != nil {
+			log.Printf("failed to check types of package: %v error: %v", pkg.ID, err.Error())
+		}
+	}
+}
+
+// lookupInterface 在包以及包的导入中查找接口
+func lookupInterface(pkgs []*packages.Package, interfaceName string) (*types.Interface, error) {
+	qualifier, name := "", interfaceName
+	if index := strings.LastIndex(interfaceName, "."); index >= 0 {
+		qualifier, name = interfaceName[:index], interfaceName[index+1:]
+	}
+
+	tps := make([]*types.Package, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		tps = append(tps, pkg.Types)
+	}
+	// 按层广度优先查找，先查找当前包，找不到时再从导入的包查找，每个包只查找一次
+	var iface *types.Interface
+	visited := make(map[*types.Package]bool)
+	for len(tps) > 0 && iface == nil {
+		next := make([]*types.Package, 0)
+		for _, tp := range tps {
+			if tp == nil || visited[tp] {
+				continue
+			}
+			visited[tp] = true
+			next = append(next, tp.Imports()...)
+			if qualifier != "" && qualifier != tp.Path() && qualifier != tp.Name() {
+				continue
+			}
+			if obj, ok := tp.Scope().Lookup(name).(*types.TypeName); ok {
+				if it, ok := obj.Type().Underlying().(*types.Interface); ok {
+					iface = it
+					break
+				}
+			}
+		}
+		tps = next
+	}
+	if iface == nil {
+		return nil, fmt.Errorf("not found interface: %v", interfaceName)
+	}
+	return iface, nil
+}
@@ -0,0 +1,83 @@
+package astutil
+
+import (
+	"testing"
+)
+
+func TestFindImplementers(t *testing.T) {
+	pkgs, err := ParsePackage([]string{"pattern=./testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkgs) == 0 {
+		t.Fatal("no package")
+	}
+	tests := []struct {
+		name          string
+		interfaceName string
+		want          map[string]bool
+		wantErr       bool
+	}{
+		{
+			name:          "local interface",
+			interfaceName: "ReadCloser",
+			want: map[string]bool{
+				"AliasImpl":          false,
+				"Base":               true,
+				"EmbedImpl":          true,
+				"EmbedInterfaceImpl": false,
+				"EmbedPointerImpl":   false,
+				"PointerImpl":        true,
+				"ShallowImpl":        true,
+			},
+		},
+		{
+			name:          "imported interface",
+			interfaceName: "io.Closer",
+			want: map[string]bool{
+				"AliasImpl":          false,
+				"AmbiguousImpl":      true,
+				"Base":               true,
+				"EmbedImpl":          true,
+				"EmbedInterfaceImpl": false,
+				"EmbedPointerImpl":   false,
+				"PointerImpl":        true,
+				"ShallowImpl":        true,
+			},
+		},
+		{
+			name:          "not found",
+			interfaceName: "NotFound",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				implementers, err := FindImplementers(pkgs, tt.interfaceName)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("FindImplementers() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					return
+				}
+				if len(implementers) != len(tt.want) {
+					t.Fatalf("FindImplementers() = %v, want %v", implementers, tt.want)
+				}
+				for _, implementer := range implementers {
+					pointer, ok := tt.want[implementer.Name]
+					if !ok {
+						t.Errorf("unexpected implementer: %v", implementer)
+						continue
+					}
+					if implementer.Pointer != pointer {
+						t.Errorf("implementer: %v pointer = %v, want %v", implementer.Name, implementer.Pointer, pointer)
+					}
+					if implementer.Position.Line == 0 {
+						t.Errorf("implementer: %v has no position", implementer.Name)
+					}
+				}
+			},
+		)
+	}
+}
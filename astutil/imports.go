@@ -0,0 +1,94 @@
+package astutil
+
+import (
+	"fmt"
+	"go/types"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// qualifierPattern 类型代码中的包名，例如 map[string]*packages.Package 中的 packages
+var qualifierPattern = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\.[A-Za-z_]`)
+
+// majorVersionPattern 导入路径中的主版本号，例如 github.com/foo/bar/v2 中的 v2
+var majorVersionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+// ImportName 导入路径默认的包名，例如 golang.org/x/tools/go/packages -> packages
+func ImportName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	// 去掉主版本号，例如 github.com/foo/bar/v2 -> bar
+	if len(elems) > 1 && majorVersionPattern.MatchString(name) {
+		name = elems[len(elems)-2]
+	}
+	// 例如 gopkg.in/yaml.v2 -> yaml
+	if index := strings.Index(name, "."); index > 0 {
+		name = name[:index]
+	}
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// importsByQualifier 包名和导入代码的映射，例如 packages -> "golang.org/x/tools/go/packages"
+func (p Parser) importsByQualifier() map[string]string {
+	imports := make(map[string]string)
+	for _, is := range p.Imports {
+		fields := strings.Fields(is)
+		if len(fields) == 2 {
+			imports[fields[0]] = is
+			continue
+		}
+		importPath, err := strconv.Unquote(is)
+		if err != nil {
+			continue
+		}
+		imports[ImportName(importPath)] = is
+	}
+	if p.p.Types == nil {
+		return imports
+	}
+	// 内嵌其他包的接口时，方法签名可能会用到当前包没有导入的包
+	visited := make(map[*types.Package]bool)
+	var walk func(pkgs []*types.Package)
+	walk = func(pkgs []*types.Package) {
+		for _, pkg := range pkgs {
+			if visited[pkg] {
+				continue
+			}
+			visited[pkg] = true
+			if _, ok := imports[pkg.Name()]; !ok {
+				is := strconv.Quote(pkg.Path())
+				if ImportName(pkg.Path()) != pkg.Name() {
+					is = pkg.Name() + " " + is
+				}
+				imports[pkg.Name()] = is
+			}
+			walk(pkg.Imports())
+		}
+	}
+	walk(p.p.Types.Imports())
+	return imports
+}
+
+// UsedImports 查找类型代码中使用到的导入，返回排序后的导入代码，例如 `"io"`、`stdio "io"`
+func (p Parser) UsedImports(typeCodes []string) ([]string, error) {
+	imports := p.importsByQualifier()
+	used := make(map[string]bool)
+	for _, code := range typeCodes {
+		for _, match := range qualifierPattern.FindAllStringSubmatch(code, -1) {
+			qualifier := match[1]
+			is, ok := imports[qualifier]
+			if !ok {
+				return nil, fmt.Errorf("not found import of package: %v in type: %v", qualifier, code)
+			}
+			used[is] = true
+		}
+	}
+	rs := make([]string, 0, len(used))
+	for is := range used {
+		rs = append(rs, is)
+	}
+	sort.Strings(rs)
+	return rs, nil
+}
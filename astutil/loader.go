@@ -0,0 +1,262 @@
+package astutil
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// loadOptions 加载包的选项
+type loadOptions struct {
+	ctx        context.Context
+	dir        string
+	env        []string
+	buildFlags []string
+	tags       []string
+	tests      bool
+	checkTypes bool
+	overlay    map[string][]byte
+}
+
+// LoadOption 加载包的选项
+type LoadOption func(o *loadOptions)
+
+// WithContext 设置加载包的上下文，用于取消加载
+func WithContext(ctx context.Context) LoadOption {
+	return func(o *loadOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithDir 设置执行构建工具的工作目录，默认为当前目录
+func WithDir(dir string) LoadOption {
+	return func(o *loadOptions) {
+		o.dir = dir
+	}
+}
+
+// WithEnv 设置执行构建工具的环境变量，默认为当前进程的环境变量
+func WithEnv(env []string) LoadOption {
+	return func(o *loadOptions) {
+		o.env = env
+	}
+}
+
+// WithBuildFlags 追加构建参数，例如 -mod=vendor
+func WithBuildFlags(flags ...string) LoadOption {
+	return func(o *loadOptions) {
+		o.buildFlags = append(o.buildFlags, flags...)
+	}
+}
+
+// WithTags 追加构建标签
+func WithTags(tags ...string) LoadOption {
+	return func(o *loadOptions) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// WithTests 是否加载测试文件和测试包
+func WithTests(tests bool) LoadOption {
+	return func(o *loadOptions) {
+		o.tests = tests
+	}
+}
+
+// WithCheckTypes 是否在加载后进行类型检查，默认为true，类型检查的错误也会返回
+func WithCheckTypes(checkTypes bool) LoadOption {
+	return func(o *loadOptions) {
+		o.checkTypes = checkTypes
+	}
+}
+
+// WithOverlay 设置文件内容的覆盖，key是文件的绝对路径，value是文件内容。
+// 可以覆盖已存在的文件，也可以在已存在的目录中添加新文件，用于扫描还没有保存的代码
+func WithOverlay(overlay map[string][]byte) LoadOption {
+	return func(o *loadOptions) {
+		if o.overlay == nil {
+			o.overlay = make(map[string][]byte, len(overlay))
+		}
+		for file, content := range overlay {
+			o.overlay[file] = content
+		}
+	}
+}
+
+// PackageErrors 加载包时的所有错误，包括语法错误和类型错误，每个错误都带有位置
+type PackageErrors []packages.Error
+
+func (e PackageErrors) Error() string {
+	sb := strings.Builder{}
+	for i, err := range e {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(err.Error())
+	}
+	return sb.String()
+}
+
+// LoadPackages 根据patterns加载包，返回所有包的错误，错误类型为 PackageErrors。
+// 有错误时也会返回已加载的包，调用方可以决定是否忽略错误
+func LoadPackages(patterns []string, opts ...LoadOption) ([]*packages.Package, error) {
+	o := &loadOptions{
+		ctx:        context.Background(),
+		checkTypes: true,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	fset := token.NewFileSet()
+	buildFlags := append([]string{}, o.buildFlags...)
+	if len(o.tags) > 0 {
+		buildFlags = append(buildFlags, fmt.Sprintf("-tags=%s", strings.Join(o.tags, ",")))
+	}
+	cfg := &packages.Config{
+		// 依赖也加载语法树，CheckTypes 根据 Imports 检查依赖，这样 Env、BuildFlags、Overlay 对依赖同样生效
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedImports | packages.NeedDeps,
+		Context:    o.ctx,
+		Dir:        o.dir,
+		Env:        o.env,
+		BuildFlags: buildFlags,
+		Fset:       fset,
+		Tests:      o.tests,
+		Overlay:    o.overlay,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs PackageErrors
+	for _, pkg := range pkgs {
+		// 类型检查时需要与语法树一致的 FileSet
+		if pkg.Fset == nil {
+			pkg.Fset = fset
+		}
+		if o.checkTypes && len(pkg.Errors) == 0 {
+			// 类型检查的错误会追加到 pkg.Errors
+			_ = CheckTypes(pkg)
+		}
+		errs = append(errs, pkg.Errors...)
+	}
+	if len(errs) > 0 {
+		return pkgs, errs
+	}
+	return pkgs, nil
+}
+
+// LoadSource 根据源码构建内存中的包，不需要文件存在于磁盘，key是文件名，value是文件内容。
+// 导入的包由 go/packages 加载，错误类型为 PackageErrors，有错误时也会返回构建的包
+func LoadSource(pkgPath string, files map[string]string) (*packages.Package, error) {
+	fileNames := make([]string, 0, len(files))
+	for fileName := range files {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+
+	fset := token.NewFileSet()
+	pkg := &packages.Package{
+		ID:      pkgPath,
+		PkgPath: pkgPath,
+		Fset:    fset,
+	}
+	for _, fileName := range fileNames {
+		file, err := parser.ParseFile(fset, fileName, files[fileName], parser.ParseComments)
+		if err != nil {
+			// 有语法错误的文件不参与扫描
+			pkg.Errors = append(pkg.Errors, parseErrors(err)...)
+			continue
+		}
+		if pkg.Name == "" {
+			pkg.Name = file.Name.Name
+		} else if pkg.Name != file.Name.Name {
+			pkg.Errors = append(
+				pkg.Errors, packages.Error{
+					Pos: fset.Position(file.Name.Pos()).String(),
+					Msg: fmt.Sprintf(
+						"found packages %v and %v in package: %v", pkg.Name, file.Name.Name, pkgPath,
+					),
+					Kind: packages.ListError,
+				},
+			)
+			continue
+		}
+		pkg.GoFiles = append(pkg.GoFiles, fileName)
+		pkg.CompiledGoFiles = append(pkg.CompiledGoFiles, fileName)
+		pkg.Syntax = append(pkg.Syntax, file)
+	}
+	if len(pkg.Errors) == 0 {
+		loadImports(pkg)
+	}
+	if len(pkg.Errors) == 0 {
+		// 类型检查的错误会追加到 pkg.Errors
+		_ = CheckTypes(pkg)
+	}
+	if len(pkg.Errors) > 0 {
+		return pkg, PackageErrors(pkg.Errors)
+	}
+	return pkg, nil
+}
+
+// loadImports 加载源码导入的包，补全 pkg.Imports，加载的错误会追加到 pkg.Errors
+func loadImports(pkg *packages.Package) {
+	pkg.Imports = make(map[string]*packages.Package)
+	seen := make(map[string]bool)
+	paths := make([]string, 0)
+	for _, file := range pkg.Syntax {
+		for _, is := range file.Imports {
+			path, err := strconv.Unquote(is.Path.Value)
+			if err != nil || path == "unsafe" || seen[path] {
+				continue
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedImports | packages.NeedDeps,
+		Fset: pkg.Fset,
+	}
+	imports, err := packages.Load(cfg, paths...)
+	if err != nil {
+		pkg.Errors = append(pkg.Errors, packages.Error{Msg: err.Error(), Kind: packages.ListError})
+		return
+	}
+	for _, ipkg := range imports {
+		pkg.Imports[ipkg.PkgPath] = ipkg
+		pkg.Errors = append(pkg.Errors, ipkg.Errors...)
+	}
+}
+
+// parseErrors 把语法错误转换为包的错误
+func parseErrors(err error) []packages.Error {
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		return []packages.Error{{Msg: err.Error(), Kind: packages.ParseError}}
+	}
+	errs := make([]packages.Error, 0, len(list))
+	for _, e := range list {
+		errs = append(
+			errs, packages.Error{
+				Pos:  e.Pos.String(),
+				Msg:  e.Msg,
+				Kind: packages.ParseError,
+			},
+		)
+	}
+	return errs
+}
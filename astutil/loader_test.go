@@ -0,0 +1,137 @@
+package astutil
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestLoadPackages(t *testing.T) {
+	pkgs, err := LoadPackages([]string{"pattern=./testdata"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkgs) != 1 || pkgs[0].Types == nil || pkgs[0].TypesInfo == nil {
+		t.Fatalf("pkgs = %v", pkgs)
+	}
+
+	pkgs, err = LoadPackages([]string{"pattern=./testdata/broken"})
+	var errs PackageErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want PackageErrors", err)
+	}
+	if len(pkgs) != 1 || len(errs) != 2 {
+		t.Fatalf("pkgs = %v errors = %v", pkgs, errs)
+	}
+	for _, e := range errs {
+		if e.Kind != packages.TypeError || !strings.Contains(e.Pos, "broken.go:") {
+			t.Errorf("error = %#v", e)
+		}
+	}
+
+	_, err = LoadPackages([]string{"pattern=./testdata/broken"}, WithCheckTypes(false))
+	if err != nil {
+		t.Errorf("error = %v, want nil without type check", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = LoadPackages([]string{"pattern=./testdata"}, WithContext(ctx)); err == nil {
+		t.Errorf("error = nil, want canceled")
+	}
+}
+
+func TestLoadPackagesOverlay(t *testing.T) {
+	dir, err := filepath.Abs("./testdata")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	overlay := map[string][]byte{
+		filepath.Join(dir, "overlay.go"): []byte("package testdata\n\n// Overlay unsaved type\ntype Overlay struct{}\n"),
+	}
+	pkgs, err := LoadPackages([]string{"pattern=./testdata"}, WithOverlay(overlay))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkgs) != 1 || pkgs[0].Types.Scope().Lookup("Overlay") == nil {
+		t.Errorf("not found type of overlay: %v", pkgs)
+	}
+
+	// 依赖的包也使用覆盖的内容
+	overlay = map[string][]byte{
+		filepath.Join(dir, "dep", "overlay.go"): []byte("package dep\n\n// Overlay unsaved type\ntype Overlay struct{ Name string }\n"),
+		filepath.Join(dir, "overlay.go"): []byte(
+			"package testdata\n\nimport \"github.com/pjoc-team/ast/astutil/testdata/dep\"\n\n" +
+				"// Overlay unsaved type of unsaved dependency type\ntype Overlay dep.Overlay\n",
+		),
+	}
+	pkgs, err = LoadPackages([]string{"pattern=./testdata"}, WithOverlay(overlay))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	obj := pkgs[0].Types.Scope().Lookup("Overlay")
+	if obj == nil || obj.Type().Underlying().String() != "struct{Name string}" {
+		t.Errorf("not found type of overlaid dependency: %v", obj)
+	}
+}
+
+func TestLoadSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "ok",
+			files: map[string]string{
+				"a.go": "package mem\n\nimport \"io\"\n\n// A type\ntype A struct{ R io.Reader }\n",
+				"b.go": "package mem\n\n// B type\ntype B A\n",
+			},
+		},
+		{
+			name: "type error",
+			files: map[string]string{
+				"a.go": "package mem\n\nvar A int = \"a\"\n",
+			},
+			wantErr: "a.go:3:",
+		},
+		{
+			name: "syntax error",
+			files: map[string]string{
+				"a.go": "package mem\n\ntype A struct{\n",
+			},
+			wantErr: "a.go:3:",
+		},
+		{
+			name: "package mismatch",
+			files: map[string]string{
+				"a.go": "package mem\n",
+				"b.go": "package other\n",
+			},
+			wantErr: "found packages mem and other",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				pkg, err := LoadSource("example.com/mem", tt.files)
+				if tt.wantErr == "" {
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+					if pkg.Name != "mem" || len(pkg.Syntax) != 2 || pkg.Types.Scope().Lookup("B") == nil {
+						t.Errorf("pkg = %#v", pkg)
+					}
+					return
+				}
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
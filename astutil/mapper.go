@@ -17,10 +17,24 @@ type FuncToken struct {
 	InTypes       []string
 	InArgAndTypes []string
 	OutTypes      []string
+
+	// PointerReceiver 是否是指针接收者的方法
+	PointerReceiver bool
+
+	// TypeParamNames 泛型函数的类型参数名，例如 [K, V]
+	TypeParamNames []string
+	// TypeParams 泛型函数的类型参数和约束，例如 [K comparable, V any]
+	TypeParams []string
 }
 
 // BuildSignature 唯一标识
 func (t FuncToken) BuildSignature() string {
+	if len(t.TypeParams) > 0 {
+		return fmt.Sprintf(
+			"%s[%s](%s) (%s)", t.FuncName, buildArray(t.TypeParams), buildArray(t.InTypes),
+			buildArray(t.OutTypes),
+		)
+	}
 	return fmt.Sprintf("%s(%s) (%s)", t.FuncName, buildArray(t.InTypes), buildArray(t.OutTypes))
 }
 
@@ -76,32 +90,39 @@ func ParseInterfaceFunc(itype *ast.InterfaceType) ([]*FuncToken, error) {
 // BuildFuncCode 构建函数代码
 func BuildFuncCode(fType *ast.FuncType) (token *FuncToken, err error) {
 	token = &FuncToken{}
-	for i, f := range fType.Params.List {
+	token.TypeParamNames, token.TypeParams, err = TypeParams(fType.TypeParams)
+	if err != nil {
+		return nil, err
+	}
+	index := 0
+	for _, f := range fType.Params.List {
 		fieldType, err := FieldType(f.Type)
 		if err != nil {
 			return nil, err
 		}
-		var argName string
-		if len(f.Names) == 0 {
-			argName = fmt.Sprintf("a%d", i)
+		argNames := make([]string, 0, len(f.Names))
+		for _, name := range f.Names {
+			argNames = append(argNames, name.Name)
+		}
+		if len(argNames) == 0 {
+			argName := fmt.Sprintf("a%d", index)
 			for isFieldNameExists(fType, argName) { // generate the unique arg name
-				argName = fmt.Sprintf("%s%d", argName, i)
+				argName = fmt.Sprintf("%s%d", argName, index)
 			}
-		} else {
-			argName = f.Names[0].Name
+			argNames = append(argNames, argName)
+		}
+		for _, argName := range argNames {
+			fieldToken := argName + " " + fieldType
+			token.InArgNames = append(token.InArgNames, argName)
+			token.InArgAndTypes = append(token.InArgAndTypes, fieldToken)
+			token.InTypes = append(token.InTypes, fieldType)
+			index++
 		}
-		fieldToken := argName + " " + fieldType
-		token.InArgNames = append(token.InArgNames, argName)
-		token.InArgAndTypes = append(token.InArgAndTypes, fieldToken)
-		token.InTypes = append(token.InTypes, fieldType)
 	}
 
-	for _, f := range fType.Results.List {
-		fieldToken, err := FieldType(f.Type)
-		if err != nil {
-			return nil, err
-		}
-		token.OutTypes = append(token.OutTypes, fieldToken)
+	token.OutTypes, err = fieldListTypes(fType.Results)
+	if err != nil {
+		return nil, err
 	}
 	return
 }
@@ -114,6 +135,9 @@ func isFieldNameExists(fType *ast.FuncType, argName string) bool {
 			}
 		}
 	}
+	if fType.Results == nil {
+		return false
+	}
 	for _, field := range fType.Results.List {
 		for _, name := range field.Names {
 			if argName == name.Name {
@@ -188,6 +212,21 @@ func ValueType(node ast.Node) (string, error) {
 	case *ast.CallExpr: // _ = errors.New("")
 		return "", nil
 	case *ast.ParenExpr: // _ = (*regexp.Regexp)(nil)
+		return ValueType(tp.X)
+	case *ast.BinaryExpr:
+		switch tp.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR:
+			return "bool", nil
+		}
+		// 其他运算的结果类型取决于操作数的类型，例如 time.Second * 2、2 * 1.5，需要类型信息才能确定
+		return "", nil
+	case *ast.TypeAssertExpr:
+		if tp.Type == nil {
+			return "", nil
+		}
+		return FieldType(tp.Type)
+	case *ast.IndexExpr, *ast.IndexListExpr, *ast.SliceExpr, *ast.KeyValueExpr:
+		// 需要类型信息才能确定类型
 		return "", nil
 	case *ast.Ident:
 		return FieldType(tp)
@@ -198,64 +237,18 @@ func ValueType(node ast.Node) (string, error) {
 	return "", nil
 }
 
+// ParseValue 解析值的类型和代码，代码使用 go/printer 生成，见 ExprString
 func ParseValue(node ast.Node) (*ValueSpec, error) {
-
 	v := &ValueSpec{}
 	valueType, err := ValueType(node)
 	if err != nil {
 		return nil, err
 	}
 	v.Type = valueType
-	sb := &strings.Builder{}
-	switch tp := node.(type) {
-	case *ast.BasicLit:
-		v.Value = tp.Value
-	case *ast.UnaryExpr:
-		sb := &strings.Builder{}
-		sb.WriteString(tp.Op.String())
-		value, err := ParseValue(tp.X)
-		if err != nil {
-			return nil, err
-		}
-		sb.WriteString(value.Value)
-		v.Value = sb.String()
-	// case *ast.FuncLit: // ignore
-	// 	fieldType, err := FieldType(tp.Type)
-	// 	if err != nil {
-	// 		return nil, err
-	// 	}
-	// 	v.Type = fieldType
-	case *ast.CompositeLit:
-		sb.WriteString(valueType)
-		sb.WriteString("{}")
-		v.Value = sb.String()
-	case *ast.SelectorExpr:
-		v.Value = valueType
-	case *ast.CallExpr:
-		s, err := ValueType(tp.Fun)
-		if err != nil {
-			log.Printf("failed to build value type: %T error: %v", tp.Fun, err.Error())
-			return nil, err
-		}
-		sb.WriteString(s)
-		sb.WriteString("(")
-		for i, arg := range tp.Args {
-			if i != 0 {
-				sb.WriteString(", ")
-			}
-			as, err := ParseValue(arg)
-			if err != nil {
-				log.Printf("failed to build value type: %T error: %v", arg, err.Error())
-				return nil, err
-			}
-
-			sb.WriteString(as.Value)
-		}
-		sb.WriteString(")")
-		v.Value = sb.String()
-	default:
-		log.Printf("unknown type: %v when parse value", reflect.TypeOf(node))
-		// return nil, fmt.Errorf("unknown type: %v when parse value", reflect.TypeOf(node))
+	v.Value, err = ExprString(node)
+	if err != nil {
+		log.Printf("failed to print value: %T error: %v", node, err.Error())
+		return nil, err
 	}
 	return v, nil
 }
@@ -265,7 +258,15 @@ func FieldType(node ast.Node) (string, error) {
 	b := strings.Builder{}
 	switch tp := node.(type) {
 	case *ast.ArrayType:
-		b.WriteString("[]")
+		b.WriteString("[")
+		if tp.Len != nil {
+			l, err := ArrayLen(tp.Len)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(l)
+		}
+		b.WriteString("]")
 		childType, err := FieldType(tp.Elt)
 		if err != nil {
 			return "", err
@@ -273,9 +274,59 @@ func FieldType(node ast.Node) (string, error) {
 		b.WriteString(childType)
 		return b.String(), nil
 	case *ast.InterfaceType:
-		return "interface{}", nil
+		return interfaceType(tp)
 	case *ast.Ident:
 		return tp.Name, nil
+	case *ast.IndexExpr:
+		// 泛型实例化，例如 List[T]
+		x, err := FieldType(tp.X)
+		if err != nil {
+			return "", err
+		}
+		index, err := FieldType(tp.Index)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s[%s]", x, index), nil
+	case *ast.IndexListExpr:
+		// 多个类型参数的泛型实例化，例如 Map[K, V]
+		x, err := FieldType(tp.X)
+		if err != nil {
+			return "", err
+		}
+		indices := make([]string, 0, len(tp.Indices))
+		for _, index := range tp.Indices {
+			it, err := FieldType(index)
+			if err != nil {
+				return "", err
+			}
+			indices = append(indices, it)
+		}
+		return fmt.Sprintf("%s[%s]", x, strings.Join(indices, ", ")), nil
+	case *ast.BinaryExpr:
+		// 类型约束的并集，例如 ~int | ~string
+		if tp.Op != token.OR {
+			return "", fmt.Errorf("unknown operator: %v when parse field token", tp.Op)
+		}
+		x, err := FieldType(tp.X)
+		if err != nil {
+			return "", err
+		}
+		y, err := FieldType(tp.Y)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s | %s", x, y), nil
+	case *ast.UnaryExpr:
+		// 类型约束的底层类型，例如 ~int
+		if tp.Op != token.TILDE {
+			return "", fmt.Errorf("unknown operator: %v when parse field token", tp.Op)
+		}
+		x, err := FieldType(tp.X)
+		if err != nil {
+			return "", err
+		}
+		return "~" + x, nil
 	case *ast.SelectorExpr:
 		prefix, ok := tp.X.(*ast.Ident)
 		if !ok {
@@ -289,35 +340,22 @@ func FieldType(node ast.Node) (string, error) {
 		}
 		return "*" + elem, nil
 	case *ast.FuncType:
-		pb := strings.Builder{}
-		delimiter := ""
-		for _, field := range tp.Params.List {
-			fieldType, err := FieldType(field.Type)
-			if err != nil {
-				return "", err
-			}
-			pb.WriteString(delimiter)
-			pb.WriteString(fieldType)
-			delimiter = ","
+		params, err := fieldListTypes(tp.Params)
+		if err != nil {
+			return "", err
 		}
-		params := pb.String()
-
-		rb := strings.Builder{}
-		delimiter = ""
-		for _, field := range tp.Results.List {
-			fieldType, err := FieldType(field.Type)
-			if err != nil {
-				return "", err
-			}
-			rb.WriteString(delimiter)
-			rb.WriteString(fieldType)
-			delimiter = ","
+		results, err := fieldListTypes(tp.Results)
+		if err != nil {
+			return "", err
 		}
-		results := rb.String()
-		if len(tp.Results.List) > 1 {
-			results = "(" + results + ")"
+		switch len(results) {
+		case 0:
+			return fmt.Sprintf("func(%s)", buildArray(params)), nil
+		case 1:
+			return fmt.Sprintf("func(%s) %s", buildArray(params), results[0]), nil
+		default:
+			return fmt.Sprintf("func(%s) (%s)", buildArray(params), buildArray(results)), nil
 		}
-		return fmt.Sprintf("func(%s) %s", params, results), nil
 	case *ast.Ellipsis:
 		elt, err := FieldType(tp.Elt)
 		if err != nil {
@@ -334,16 +372,46 @@ func FieldType(node ast.Node) (string, error) {
 			return "", err
 		}
 		return fmt.Sprintf("map[%s]%s", kt, vt), nil
+	case *ast.ChanType:
+		elem, err := FieldType(tp.Value)
+		if err != nil {
+			return "", err
+		}
+		switch tp.Dir {
+		case ast.RECV:
+			return "<-chan " + elem, nil
+		case ast.SEND:
+			return "chan<- " + elem, nil
+		default:
+			// chan (<-chan T) 必须加括号，否则会被解析为 chan<- chan T
+			value := tp.Value
+			for paren, ok := value.(*ast.ParenExpr); ok; paren, ok = value.(*ast.ParenExpr) {
+				value = paren.X
+			}
+			if inner, ok := value.(*ast.ChanType); ok && inner.Dir == ast.RECV {
+				return "chan (" + elem + ")", nil
+			}
+			return "chan " + elem, nil
+		}
+	case *ast.ParenExpr:
+		return FieldType(tp.X)
 	case *ast.StructType:
 		sb := strings.Builder{}
-		for _, field := range tp.Fields.List {
-			sb.WriteString("\n")
-			ft, err := FieldType(field)
+		for i, field := range tp.Fields.List {
+			if i > 0 {
+				sb.WriteString("; ")
+			}
+			ft, err := FieldType(field.Type)
 			if err != nil {
 				return "", err
 			}
+			for j, name := range field.Names {
+				if j > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteString(name.Name)
+			}
 			if len(field.Names) > 0 {
-				sb.WriteString(field.Names[0].Name)
 				sb.WriteString(" ")
 			}
 			sb.WriteString(ft)
@@ -353,3 +421,94 @@ func FieldType(node ast.Node) (string, error) {
 		return "", fmt.Errorf("unknown type: %v when parse field token", reflect.TypeOf(node))
 	}
 }
+
+// interfaceType 生成接口类型的代码，展开方法和类型约束，例如 interface {Close() error}、
+// interface {~int | ~string}，没有方法时为 interface{}
+func interfaceType(it *ast.InterfaceType) (string, error) {
+	if it.Methods == nil || len(it.Methods.List) == 0 {
+		return "interface{}", nil
+	}
+	elems := make([]string, 0, len(it.Methods.List))
+	for _, field := range it.Methods.List {
+		ft, err := FieldType(field.Type)
+		if err != nil {
+			return "", err
+		}
+		if len(field.Names) > 0 {
+			// 方法，去掉 func 前缀，例如 String() string
+			ft = field.Names[0].Name + strings.TrimPrefix(ft, "func")
+		}
+		elems = append(elems, ft)
+	}
+	return fmt.Sprintf("interface {%s}", strings.Join(elems, "; ")), nil
+}
+
+// TypeParams 解析类型参数列表，返回类型参数名和带约束的代码，例如 [K comparable, V any] ->
+// [K, V] 和 [K comparable, V any]
+func TypeParams(list *ast.FieldList) (names []string, params []string, err error) {
+	if list == nil {
+		return nil, nil, nil
+	}
+	for _, field := range list.List {
+		constraint, err := FieldType(field.Type)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+			params = append(params, name.Name+" "+constraint)
+		}
+	}
+	return names, params, nil
+}
+
+// fieldListTypes 参数列表的类型，多个参数共用一个类型时会重复展开，例如 (a, b int) -> [int, int]
+func fieldListTypes(list *ast.FieldList) ([]string, error) {
+	if list == nil {
+		return nil, nil
+	}
+	types := make([]string, 0, list.NumFields())
+	for _, field := range list.List {
+		fieldType, err := FieldType(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, fieldType)
+		for i := 1; i < len(field.Names); i++ {
+			types = append(types, fieldType)
+		}
+	}
+	return types, nil
+}
+
+// ArrayLen 生成数组长度的代码，例如 [4]int 的 4，[...]int 的 ...
+func ArrayLen(node ast.Expr) (string, error) {
+	switch tp := node.(type) {
+	case *ast.BasicLit:
+		return tp.Value, nil
+	case *ast.Ident:
+		return tp.Name, nil
+	case *ast.Ellipsis:
+		return "...", nil
+	case *ast.SelectorExpr:
+		return FieldType(tp)
+	case *ast.ParenExpr:
+		x, err := ArrayLen(tp.X)
+		if err != nil {
+			return "", err
+		}
+		return "(" + x + ")", nil
+	case *ast.BinaryExpr:
+		x, err := ArrayLen(tp.X)
+		if err != nil {
+			return "", err
+		}
+		y, err := ArrayLen(tp.Y)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s %s %s", x, tp.Op, y), nil
+	default:
+		return "", fmt.Errorf("unknown type: %v when parse array length", reflect.TypeOf(node))
+	}
+}
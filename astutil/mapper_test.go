@@ -3,9 +3,10 @@ package astutil
 import (
 	"fmt"
 	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
 	"testing"
-
-	"golang.org/x/tools/go/packages"
 )
 
 func a() (*string, error) {
@@ -13,13 +14,9 @@ func a() (*string, error) {
 }
 
 func TestFieldType(t *testing.T) {
-	cfg := &packages.Config{
-		Mode: packages.LoadSyntax,
-		// TODO: Need to think about constants in test files. Maybe write type_string_test.go
-		// in a separate pass? For later.
-		Tests: true,
-	}
-	pkgs, err := packages.Load(cfg, "./mapper_test.go")
+	// TODO: Need to think about constants in test files. Maybe write type_string_test.go
+	// in a separate pass? For later.
+	pkgs, err := LoadPackages([]string{"./mapper_test.go"}, WithTests(true), WithCheckTypes(false))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -52,13 +49,9 @@ func TestFieldType(t *testing.T) {
 }
 
 func TestValueType(t *testing.T) {
-	cfg := &packages.Config{
-		Mode: packages.LoadSyntax,
-		// TODO: Need to think about constants in test files. Maybe write type_string_test.go
-		// in a separate pass? For later.
-		Tests: true,
-	}
-	pkgs, err := packages.Load(cfg, "./testdata/testdata.go")
+	// TODO: Need to think about constants in test files. Maybe write type_string_test.go
+	// in a separate pass? For later.
+	pkgs, err := LoadPackages([]string{"./testdata/testdata.go"}, WithTests(true), WithCheckTypes(false))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -88,16 +81,9 @@ func TestValueType(t *testing.T) {
 }
 
 func TestPbValueType(t *testing.T) {
-	cfg := &packages.Config{
-		Mode: packages.LoadSyntax,
-		// TODO: Need to think about constants in test files. Maybe write type_string_test.go
-		// in a separate pass? For later.
-		Tests: true,
-	}
-	pkgs, err := packages.Load(cfg, "github.com/pjoc-team/ast")
-	if err != nil {
-		t.Fatal(err)
-	}
+	// TODO: Need to think about constants in test files. Maybe write type_string_test.go
+	// in a separate pass? For later.
+	pkgs, _ := LoadPackages([]string{"github.com/pjoc-team/ast"}, WithTests(true), WithCheckTypes(false))
 	for _, pkg := range pkgs {
 		for _, file := range pkg.Syntax {
 			decls := file.Decls
@@ -122,3 +108,155 @@ func TestPbValueType(t *testing.T) {
 		}
 	}
 }
+
+func TestFieldTypeChan(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{
+			name: "t1",
+			expr: "chan string",
+			want: "chan string",
+		},
+		{
+			name: "t2",
+			expr: "<-chan *foo.Bar",
+			want: "<-chan *foo.Bar",
+		},
+		{
+			name: "t3",
+			expr: "chan<- []int",
+			want: "chan<- []int",
+		},
+		{
+			name: "t4",
+			expr: "chan (<-chan int)",
+			want: "chan (<-chan int)",
+		},
+		{
+			name: "t5",
+			expr: "chan<- chan int",
+			want: "chan<- chan int",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				expr, err := parser.ParseExpr(tt.expr)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				got, err := FieldType(expr)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if got != tt.want {
+					t.Errorf("FieldType() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestFieldTypeLiteral(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{
+			name: "t1",
+			expr: "[4]int",
+			want: "[4]int",
+		},
+		{
+			name: "t2",
+			expr: "[size * 2][]string",
+			want: "[size * 2][]string",
+		},
+		{
+			name: "t3",
+			expr: "func()",
+			want: "func()",
+		},
+		{
+			name: "t4",
+			expr: "func(a, b int) error",
+			want: "func(int,int) error",
+		},
+		{
+			name: "t5",
+			expr: "struct{A, B int; C *foo.Bar}",
+			want: "struct {A, B int; C *foo.Bar}",
+		},
+		{
+			name: "t6",
+			expr: "*List[T]",
+			want: "*List[T]",
+		},
+		{
+			name: "t7",
+			expr: "map[string]foo.Map[K, []V]",
+			want: "map[string]foo.Map[K, []V]",
+		},
+		{
+			name: "t8",
+			expr: "interface{ ~int | ~string; String() string }",
+			want: "interface {~int | ~string; String() string}",
+		},
+		{
+			name: "t9",
+			expr: "interface{ io.Reader; Use(c interface{ Close() error }) error }",
+			want: "interface {io.Reader; Use(interface {Close() error}) error}",
+		},
+		{
+			name: "t10",
+			expr: "interface{}",
+			want: "interface{}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				expr, err := parser.ParseExpr(tt.expr)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				got, err := FieldType(expr)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if got != tt.want {
+					t.Errorf("FieldType() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestBuildFuncCodeTypeParams(t *testing.T) {
+	file, err := parser.ParseFile(
+		token.NewFileSet(), "generic.go",
+		"package p\nfunc Map[K comparable, V any, T ~int | ~string](m map[K]V, t T) []V { return nil }", 0,
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	fd := file.Decls[0].(*ast.FuncDecl)
+	code, err := BuildFuncCode(fd.Type)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	code.FuncName = fd.Name.Name
+	if got := strings.Join(code.TypeParamNames, ","); got != "K,V,T" {
+		t.Errorf("TypeParamNames = %v", got)
+	}
+	if got := code.BuildSignature(); got != "Map[K comparable,V any,T ~int | ~string](map[K]V,T) ([]V)" {
+		t.Errorf("BuildSignature() = %v", got)
+	}
+	if got := code.FuncCode(); got != "Map[K comparable, V any, T ~int | ~string](m map[K]V, t T) []V" {
+		t.Errorf("FuncCode() = %v", got)
+	}
+}
@@ -0,0 +1,367 @@
+package astutil
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+)
+
+// methodOf 如果函数是类型 typeName 的方法（包括指针接收者），则返回对应的函数
+func (p Parser) methodOf(typeName string, decl *ast.FuncDecl) (*FuncToken, bool) {
+	recv := decl.Recv
+	if recv == nil || len(recv.List) == 0 {
+		return nil, false
+	}
+	name, pointer := ReceiverTypeName(recv.List[0].Type)
+	if name != typeName {
+		return nil, false
+	}
+	code, err := BuildFuncCode(decl.Type)
+	if err != nil {
+		p.warnf("failed to build func: %v error: %v", decl.Name.Name, err.Error())
+		return nil, false
+	}
+	code.FuncName = decl.Name.Name
+	code.Ident = decl.Name
+	code.PointerReceiver = pointer
+	return code, true
+}
+
+// ReceiverTypeName 解析接收者的类型名，以及是否是指针接收者，例如 *T -> T, true、*List[T] -> List, true
+func ReceiverTypeName(expr ast.Expr) (name string, pointer bool) {
+	for {
+		switch tp := expr.(type) {
+		case *ast.StarExpr:
+			pointer = true
+			expr = tp.X
+		case *ast.ParenExpr:
+			expr = tp.X
+		case *ast.IndexExpr:
+			expr = tp.X
+		case *ast.IndexListExpr:
+			expr = tp.X
+		case *ast.Ident:
+			return tp.Name, pointer
+		default:
+			return "", pointer
+		}
+	}
+}
+
+// declaredFuncs 类型在当前包声明的方法
+func (p Parser) declaredFuncs(typeName string) []*FuncToken {
+	funcs := make([]*FuncToken, 0)
+	for _, file := range p.p.Syntax {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			if code, ok := p.methodOf(typeName, fd); ok {
+				funcs = append(funcs, code)
+			}
+		}
+	}
+	return funcs
+}
+
+// methodSets 计算类型 T 和 *T 的方法集，包括内嵌字段提升的方法，key是函数签名，
+// depths 是方法名对应的深度，声明的方法为0，内嵌字段提升的方法为内嵌的层数。
+// 规则参考 https://golang.org/ref/spec#Method_sets ：
+// 内嵌 S 时，T 和 *T 都包含 S 的方法，*T 还包含 *S 的方法；内嵌 *S 时，T 和 *T 都包含 *S 的方法。
+// 深度小的方法会屏蔽深度大的同名方法，同一深度有多个同名方法时有歧义，这些方法都不会提升
+func (p Parser) methodSets(typeName string, visited map[string]bool) (
+	value map[string]*FuncToken, pointer map[string]*FuncToken, depths map[string]int, err error,
+) {
+	value = make(map[string]*FuncToken)
+	pointer = make(map[string]*FuncToken)
+	depths = make(map[string]int)
+	if visited[typeName] {
+		return value, pointer, depths, nil
+	}
+	// 只防止内嵌的循环，不同的内嵌字段可以内嵌同一个类型
+	visited[typeName] = true
+	defer delete(visited, typeName)
+
+	for _, code := range p.declaredFuncs(typeName) {
+		depths[code.FuncName] = 0
+		pointer[code.BuildSignature()] = code
+		if !code.PointerReceiver {
+			value[code.BuildSignature()] = code
+		}
+	}
+
+	tp := p.findType(typeName)
+	if tp == nil {
+		return value, pointer, depths, nil
+	}
+	if itype, ok := tp.Type.(*ast.InterfaceType); ok {
+		funcs, err := p.interfaceFuncs(itype, make(map[*ast.InterfaceType]bool))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		addFuncs(depths, value, funcs)
+		addFuncs(depths, pointer, funcs)
+		for _, code := range funcs {
+			if _, ok := depths[code.FuncName]; !ok {
+				depths[code.FuncName] = 0
+			}
+		}
+		return value, pointer, depths, nil
+	}
+	st, ok := tp.Type.(*ast.StructType)
+	if !ok {
+		return value, pointer, depths, nil
+	}
+
+	type embedded struct {
+		value   []*FuncToken
+		pointer []*FuncToken
+		depths  map[string]int
+	}
+	embeds := make([]*embedded, 0)
+	// 每个方法名的最小深度，以及这个深度提供该方法的内嵌字段数量
+	minDepths := make(map[string]int)
+	counts := make(map[string]int)
+	for _, field := range st.Fields.List {
+		if len(field.Names) > 0 {
+			continue
+		}
+		embedValue, embedPointer, embedDepths, err := p.embeddedMethodSets(field.Type, visited)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		embeds = append(embeds, &embedded{value: embedValue, pointer: embedPointer, depths: embedDepths})
+		for name, depth := range embedDepths {
+			if min, ok := minDepths[name]; !ok || depth < min {
+				minDepths[name] = depth
+				counts[name] = 1
+			} else if depth == min {
+				counts[name]++
+			}
+		}
+	}
+	for _, embed := range embeds {
+		accept := func(funcs []*FuncToken) []*FuncToken {
+			accepted := make([]*FuncToken, 0, len(funcs))
+			for _, code := range funcs {
+				name := code.FuncName
+				if counts[name] == 1 && embed.depths[name] == minDepths[name] {
+					accepted = append(accepted, code)
+				}
+			}
+			return accepted
+		}
+		addFuncs(depths, value, accept(embed.value))
+		addFuncs(depths, pointer, accept(embed.pointer))
+	}
+	for name, depth := range minDepths {
+		if counts[name] > 1 {
+			p.warnf("ambiguous method: %v of type: %v", name, typeName)
+		}
+		// 有歧义的方法名同样会屏蔽更深的同名方法
+		if _, ok := depths[name]; !ok {
+			depths[name] = depth + 1
+		}
+	}
+	return value, pointer, depths, nil
+}
+
+// embeddedMethodSets 内嵌字段提升到 T 和 *T 的方法，以及方法名在内嵌字段中的深度
+func (p Parser) embeddedMethodSets(expr ast.Expr, visited map[string]bool) (
+	value []*FuncToken, pointer []*FuncToken, depths map[string]int, err error,
+) {
+	name, isPointer := ReceiverTypeName(expr)
+	// 实例化的泛型类型需要使用类型信息替换类型参数
+	if name != "" && p.findType(name) != nil && !isInstantiated(expr) {
+		embedValue, embedPointer, embedDepths, err := p.methodSets(name, visited)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if isPointer {
+			return funcsOf(embedPointer), funcsOf(embedPointer), embedDepths, nil
+		}
+		return funcsOf(embedValue), funcsOf(embedPointer), embedDepths, nil
+	}
+
+	// 其他包的类型使用类型信息
+	tp, err := p.typeOf(expr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ms := types.NewMethodSet(tp)
+	depths = make(map[string]int)
+	// 指针的方法集包含值接收者的方法，深度以指针的方法集为准
+	pms := ms
+	if _, ok := tp.(*types.Pointer); !ok && !types.IsInterface(tp) {
+		pms = types.NewMethodSet(types.NewPointer(tp))
+	}
+	for i := 0; i < pms.Len(); i++ {
+		depths[pms.At(i).Obj().Name()] = len(pms.At(i).Index()) - 1
+	}
+	value = p.typesFuncs(ms)
+	if pms == ms {
+		return value, value, depths, nil
+	}
+	return value, p.typesFuncs(pms), depths, nil
+}
+
+// isInstantiated 是否是实例化的泛型类型，例如 List[int]、*Map[string, int]
+func isInstantiated(expr ast.Expr) bool {
+	for {
+		switch tp := expr.(type) {
+		case *ast.StarExpr:
+			expr = tp.X
+		case *ast.ParenExpr:
+			expr = tp.X
+		case *ast.IndexExpr, *ast.IndexListExpr:
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+// isTypeElem 是否是类型约束中的类型元素，例如 ~int、int | string
+func isTypeElem(expr ast.Expr) bool {
+	switch expr.(type) {
+	case *ast.BinaryExpr, *ast.UnaryExpr:
+		return true
+	}
+	return false
+}
+
+// interfaceFuncs 解析接口的所有函数，包括内嵌接口的函数
+func (p Parser) interfaceFuncs(itype *ast.InterfaceType, visited map[*ast.InterfaceType]bool) (
+	[]*FuncToken, error,
+) {
+	if visited[itype] {
+		return nil, nil
+	}
+	visited[itype] = true
+
+	tokens, err := ParseInterfaceFunc(itype)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, token := range tokens {
+		names[token.FuncName] = true
+	}
+	for _, field := range itype.Methods.List {
+		if _, ok := field.Type.(*ast.FuncType); ok {
+			continue
+		}
+		var embedded []*FuncToken
+		if isTypeElem(field.Type) {
+			// 类型约束中的类型元素，例如 ~int | ~string，不提供方法
+			continue
+		} else if ident, ok := field.Type.(*ast.Ident); ok && p.findInterfaceType(ident.Name) != nil {
+			embedded, err = p.interfaceFuncs(p.findInterfaceType(ident.Name), visited)
+		} else {
+			var tp types.Type
+			tp, err = p.typeOf(field.Type)
+			if err == nil && types.IsInterface(tp) {
+				embedded = p.typesFuncs(types.NewMethodSet(tp))
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range embedded {
+			if names[token.FuncName] {
+				continue
+			}
+			names[token.FuncName] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (p Parser) typeOf(expr ast.Expr) (types.Type, error) {
+	if p.p.TypesInfo == nil {
+		return nil, fmt.Errorf("no type info to resolve type: %v", expr)
+	}
+	tp := p.p.TypesInfo.TypeOf(expr)
+	if tp == nil {
+		return nil, fmt.Errorf("not found type of: %v", expr)
+	}
+	return tp, nil
+}
+
+// typesFuncs 根据类型信息生成方法集的函数
+func (p Parser) typesFuncs(ms *types.MethodSet) []*FuncToken {
+	funcs := make([]*FuncToken, 0, ms.Len())
+	for i := 0; i < ms.Len(); i++ {
+		fn, ok := ms.At(i).Obj().(*types.Func)
+		if !ok {
+			continue
+		}
+		funcs = append(funcs, p.TypesFuncToken(fn))
+	}
+	return funcs
+}
+
+// TypesFuncToken 根据类型信息生成函数，类型的包名使用包的默认名称
+func (p Parser) TypesFuncToken(fn *types.Func) *FuncToken {
+	signature := fn.Type().(*types.Signature)
+	token := &FuncToken{
+		Ident:    &ast.Ident{Name: fn.Name(), NamePos: fn.Pos()},
+		FuncName: fn.Name(),
+	}
+	if recv := signature.Recv(); recv != nil {
+		_, token.PointerReceiver = recv.Type().(*types.Pointer)
+	}
+	params := signature.Params()
+	for i := 0; i < params.Len(); i++ {
+		v := params.At(i)
+		var fieldType string
+		if slice, ok := v.Type().(*types.Slice); ok && signature.Variadic() && i == params.Len()-1 {
+			fieldType = "..." + types.TypeString(slice.Elem(), p.qualifier)
+		} else {
+			fieldType = types.TypeString(v.Type(), p.qualifier)
+		}
+		argName := v.Name()
+		if argName == "" || argName == "_" {
+			argName = fmt.Sprintf("a%d", i)
+		}
+		token.InArgNames = append(token.InArgNames, argName)
+		token.InArgAndTypes = append(token.InArgAndTypes, argName+" "+fieldType)
+		token.InTypes = append(token.InTypes, fieldType)
+	}
+	results := signature.Results()
+	for i := 0; i < results.Len(); i++ {
+		token.OutTypes = append(token.OutTypes, types.TypeString(results.At(i).Type(), p.qualifier))
+	}
+	return token
+}
+
+// qualifier 当前包的类型不需要包名，其他包的类型使用包名
+func (p Parser) qualifier(pkg *types.Package) string {
+	if pkg == nil || pkg.Path() == p.p.PkgPath {
+		return ""
+	}
+	return pkg.Name()
+}
+
+// addFuncs 把未被屏蔽的函数加入方法集，depths 中已有的方法名会屏蔽同名的函数
+func addFuncs(depths map[string]int, set map[string]*FuncToken, funcs []*FuncToken) {
+	for _, code := range funcs {
+		if _, ok := depths[code.FuncName]; ok {
+			continue
+		}
+		signature := code.BuildSignature()
+		if _, ok := set[signature]; !ok {
+			set[signature] = code
+		}
+	}
+}
+
+func funcsOf(set map[string]*FuncToken) []*FuncToken {
+	funcs := make([]*FuncToken, 0, len(set))
+	for _, code := range set {
+		funcs = append(funcs, code)
+	}
+	return funcs
+}
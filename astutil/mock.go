@@ -0,0 +1,324 @@
+package astutil
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// mockOptions 生成mock的选项
+type mockOptions struct {
+	mockName     string
+	receiverName string
+}
+
+// MockOption 生成mock的选项
+type MockOption func(o *mockOptions)
+
+// WithMockName 设置mock的类型名，默认为 Mock + 接口名
+func WithMockName(name string) MockOption {
+	return func(o *mockOptions) {
+		o.mockName = name
+	}
+}
+
+// WithMockReceiver 设置mock方法的接收者名称，默认为 m
+func WithMockReceiver(name string) MockOption {
+	return func(o *mockOptions) {
+		o.receiverName = name
+	}
+}
+
+// Mock 生成的mock
+type Mock struct {
+	// Name mock的类型名
+	Name string `json:"name" yaml:"name"`
+
+	// Methods mock的方法名，按接口定义的顺序排列
+	Methods []string `json:"methods" yaml:"methods"`
+
+	// Imports 生成的代码需要的导入，例如 `"io"`、`"sync"`
+	Imports []string `json:"imports" yaml:"imports"`
+
+	// Code 格式化后的完整go文件，包括package和import
+	Code string `json:"code" yaml:"code"`
+}
+
+// mockMethod 生成mock方法需要的信息
+type mockMethod struct {
+	token    *FuncToken
+	callType string
+	argNames []string
+	// argTypes 参数类型，可变参数为切片类型
+	argTypes []string
+	variadic bool
+	// fnName 方法体中保存 XxxFunc 的变量名，不能和参数重名
+	fnName string
+}
+
+// GenerateMock 为当前包的接口生成mock实现，包括接口内嵌的方法。
+// 每个方法生成：
+//   - XxxFunc 字段，设置方法的实现，为nil时返回零值
+//   - XxxReturns 方法，设置固定的返回值
+//   - XxxCalls、XxxCallCount 方法，返回调用的参数和次数
+func (p Parser) GenerateMock(interfaceName string, opts ...MockOption) (*Mock, error) {
+	o := &mockOptions{
+		mockName:     "Mock" + interfaceName,
+		receiverName: "m",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	interfaceType := p.findInterfaceType(interfaceName)
+	if interfaceType == nil {
+		return nil, fmt.Errorf("not found interface: %v package: %v", interfaceName, p.p.Name)
+	}
+	tokens, err := p.interfaceFuncs(interfaceType, make(map[*ast.InterfaceType]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make([]*mockMethod, 0, len(tokens))
+	names := make(map[string]bool)
+	argNames := make(map[string]bool)
+	typeCodes := make([]string, 0)
+	for _, token := range tokens {
+		names[token.FuncName] = true
+		methods = append(methods, newMockMethod(o.mockName, token))
+		for _, argName := range token.InArgNames {
+			argNames[argName] = true
+		}
+		typeCodes = append(typeCodes, token.InTypes...)
+		typeCodes = append(typeCodes, token.OutTypes...)
+	}
+	// 生成的字段和方法不能和接口的方法重名
+	for _, method := range methods {
+		for _, suffix := range []string{"Func", "Returns", "Calls", "CallCount"} {
+			if names[method.token.FuncName+suffix] {
+				return nil, fmt.Errorf(
+					"generated name: %v conflicts with method of interface: %v",
+					method.token.FuncName+suffix, interfaceName,
+				)
+			}
+		}
+	}
+	// 接收者不能和参数重名
+	recvName := o.receiverName
+	for i := 0; argNames[recvName]; i++ {
+		recvName = fmt.Sprintf("%s%d", o.receiverName, i)
+	}
+
+	imports, err := p.UsedImports(typeCodes)
+	if err != nil {
+		return nil, err
+	}
+	imports = addImport(imports, strconv.Quote("sync"))
+
+	mock := &Mock{
+		Name:    o.mockName,
+		Imports: imports,
+	}
+	sb := &strings.Builder{}
+	sb.WriteString("// Code generated by github.com/pjoc-team/ast/astutil. DO NOT EDIT.\n\n")
+	fmt.Fprintf(sb, "package %s\n\n", p.p.Name)
+	sb.WriteString("import (\n")
+	for _, is := range imports {
+		sb.WriteString(is)
+		sb.WriteString("\n")
+	}
+	sb.WriteString(")\n\n")
+
+	fmt.Fprintf(sb, "// %s mock of %s\n", o.mockName, interfaceName)
+	fmt.Fprintf(sb, "type %s struct {\n", o.mockName)
+	for _, method := range methods {
+		fmt.Fprintf(sb, "// %sFunc implementation of %s, returns zero values if nil\n", method.token.FuncName, method.token.FuncName)
+		fmt.Fprintf(sb, "%sFunc func(%s) %s\n", method.token.FuncName, method.params(), method.results())
+	}
+	sb.WriteString("\nmu sync.Mutex\n")
+	sb.WriteString("calls struct {\n")
+	for _, method := range methods {
+		fmt.Fprintf(sb, "%s []%s\n", method.token.FuncName, method.callType)
+	}
+	sb.WriteString("}\n")
+	sb.WriteString("}\n")
+
+	receiver := recvName + " *" + o.mockName
+	for _, method := range methods {
+		mock.Methods = append(mock.Methods, method.token.FuncName)
+		method.write(sb, receiver, recvName)
+	}
+
+	code, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, err
+	}
+	mock.Code = string(code)
+	return mock, nil
+}
+
+func newMockMethod(mockName string, token *FuncToken) *mockMethod {
+	method := &mockMethod{
+		token:    token,
+		callType: mockName + token.FuncName + "Call",
+	}
+	for i, argName := range token.InArgNames {
+		if argName == "_" {
+			argName = fmt.Sprintf("a%d", i)
+		}
+		argType := token.InTypes[i]
+		if strings.HasPrefix(argType, "...") {
+			method.variadic = true
+			argType = "[]" + strings.TrimPrefix(argType, "...")
+		}
+		method.argNames = append(method.argNames, argName)
+		method.argTypes = append(method.argTypes, argType)
+	}
+	method.fnName = "fn"
+	for i := 0; containsString(method.argNames, method.fnName); i++ {
+		method.fnName = fmt.Sprintf("fn%d", i)
+	}
+	return method
+}
+
+// params 参数列表的代码，例如 p []byte, args ...interface{}
+func (m *mockMethod) params() string {
+	params := make([]string, 0, len(m.argNames))
+	for i, argName := range m.argNames {
+		params = append(params, argName+" "+m.token.InTypes[i])
+	}
+	return strings.Join(params, ", ")
+}
+
+// results 返回值列表的代码
+func (m *mockMethod) results() string {
+	switch len(m.token.OutTypes) {
+	case 0:
+		return ""
+	case 1:
+		return m.token.OutTypes[0]
+	default:
+		return "(" + strings.Join(m.token.OutTypes, ", ") + ")"
+	}
+}
+
+// args 调用时的参数代码，可变参数需要展开
+func (m *mockMethod) args() string {
+	args := make([]string, 0, len(m.argNames))
+	for i, argName := range m.argNames {
+		if m.variadic && i == len(m.argNames)-1 {
+			argName += "..."
+		}
+		args = append(args, argName)
+	}
+	return strings.Join(args, ", ")
+}
+
+func (m *mockMethod) write(sb *strings.Builder, receiver string, recvName string) {
+	name := m.token.FuncName
+
+	sb.WriteString("\n")
+	fmt.Fprintf(sb, "// %s arguments of a call to %s\n", m.callType, name)
+	fmt.Fprintf(sb, "type %s struct {\n", m.callType)
+	for i, argName := range m.argNames {
+		fmt.Fprintf(sb, "%s %s\n", exportedName(argName), m.argTypes[i])
+	}
+	sb.WriteString("}\n")
+
+	sb.WriteString("\n")
+	fmt.Fprintf(sb, "// %s records the call and calls %sFunc\n", name, name)
+	fmt.Fprintf(sb, "func (%s) %s(%s) %s {\n", receiver, name, m.params(), m.results())
+	fmt.Fprintf(sb, "%s.mu.Lock()\n", recvName)
+	fields := make([]string, 0, len(m.argNames))
+	for _, argName := range m.argNames {
+		fields = append(fields, exportedName(argName)+": "+argName)
+	}
+	fmt.Fprintf(
+		sb, "%s.calls.%s = append(%s.calls.%s, %s{%s})\n",
+		recvName, name, recvName, name, m.callType, strings.Join(fields, ", "),
+	)
+	fmt.Fprintf(sb, "%s := %s.%sFunc\n", m.fnName, recvName, name)
+	fmt.Fprintf(sb, "%s.mu.Unlock()\n", recvName)
+	if len(m.token.OutTypes) == 0 {
+		fmt.Fprintf(sb, "if %s != nil {\n%s(%s)\n}\n", m.fnName, m.fnName, m.args())
+	} else {
+		values := make([]string, 0, len(m.token.OutTypes))
+		for _, outType := range m.token.OutTypes {
+			values = append(values, ZeroValue(outType))
+		}
+		fmt.Fprintf(sb, "if %s == nil {\nreturn %s\n}\n", m.fnName, strings.Join(values, ", "))
+		fmt.Fprintf(sb, "return %s(%s)\n", m.fnName, m.args())
+	}
+	sb.WriteString("}\n")
+
+	sb.WriteString("\n")
+	fmt.Fprintf(sb, "// %sCalls returns the arguments of calls to %s\n", name, name)
+	fmt.Fprintf(sb, "func (%s) %sCalls() []%s {\n", receiver, name, m.callType)
+	fmt.Fprintf(sb, "%s.mu.Lock()\n", recvName)
+	fmt.Fprintf(sb, "defer %s.mu.Unlock()\n", recvName)
+	fmt.Fprintf(sb, "calls := make([]%s, len(%s.calls.%s))\n", m.callType, recvName, name)
+	fmt.Fprintf(sb, "copy(calls, %s.calls.%s)\n", recvName, name)
+	sb.WriteString("return calls\n")
+	sb.WriteString("}\n")
+
+	sb.WriteString("\n")
+	fmt.Fprintf(sb, "// %sCallCount returns the number of calls to %s\n", name, name)
+	fmt.Fprintf(sb, "func (%s) %sCallCount() int {\n", receiver, name)
+	fmt.Fprintf(sb, "%s.mu.Lock()\n", recvName)
+	fmt.Fprintf(sb, "defer %s.mu.Unlock()\n", recvName)
+	fmt.Fprintf(sb, "return len(%s.calls.%s)\n", recvName, name)
+	sb.WriteString("}\n")
+
+	if len(m.token.OutTypes) == 0 {
+		return
+	}
+	results := make([]string, 0, len(m.token.OutTypes))
+	returns := make([]string, 0, len(m.token.OutTypes))
+	for i, outType := range m.token.OutTypes {
+		results = append(results, fmt.Sprintf("r%d %s", i, outType))
+		returns = append(returns, fmt.Sprintf("r%d", i))
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(sb, "// %sReturns sets the values returned by %s\n", name, name)
+	fmt.Fprintf(sb, "func (%s) %sReturns(%s) {\n", receiver, name, strings.Join(results, ", "))
+	fmt.Fprintf(sb, "%s.mu.Lock()\n", recvName)
+	fmt.Fprintf(sb, "defer %s.mu.Unlock()\n", recvName)
+	fmt.Fprintf(
+		sb, "%s.%sFunc = func(%s) %s {\nreturn %s\n}\n",
+		recvName, name, strings.Join(m.token.InTypes, ", "), m.results(), strings.Join(returns, ", "),
+	)
+	sb.WriteString("}\n")
+}
+
+// exportedName 首字母大写
+func exportedName(name string) string {
+	for i, r := range name {
+		return string(unicode.ToUpper(r)) + name[i+len(string(r)):]
+	}
+	return name
+}
+
+// addImport 添加导入并排序，已存在时不重复添加
+func addImport(imports []string, is string) []string {
+	for _, i := range imports {
+		if i == is {
+			return imports
+		}
+	}
+	imports = append(imports, is)
+	sort.Strings(imports)
+	return imports
+}
+
+func containsString(ss []string, s string) bool {
+	for _, i := range ss {
+		if i == s {
+			return true
+		}
+	}
+	return false
+}
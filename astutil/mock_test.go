@@ -0,0 +1,146 @@
+package astutil
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParser_GenerateMock(t *testing.T) {
+	pkgs, err := ParsePackage([]string{"pattern=./testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkgs) == 0 {
+		t.Fatal("no package")
+	}
+	p := NewParser(pkgs[0], WithDebug(false))
+	tests := []struct {
+		name          string
+		interfaceName string
+		opts          []MockOption
+		methods       []string
+		imports       []string
+		contains      []string
+	}{
+		{
+			name:          "embedded",
+			interfaceName: "ReadCloser",
+			methods:       []string{"Read", "Close"},
+			imports:       []string{`"sync"`},
+			contains: []string{
+				"package testdata",
+				"type MockReadCloser struct {",
+				"ReadFunc func(p []byte) (int, error)",
+				"func (m *MockReadCloser) Read(p []byte) (int, error) {",
+				"func (m *MockReadCloser) ReadReturns(r0 int, r1 error) {",
+				"func (m *MockReadCloser) CloseCallCount() int {",
+				"type MockReadCloserReadCall struct {",
+			},
+		},
+		{
+			name:          "variadic",
+			interfaceName: "Logger",
+			opts:          []MockOption{WithMockName("FakeLogger")},
+			methods:       []string{"Logf", "Level", "SetOutput", "Sync"},
+			imports:       []string{`"io"`, `"sync"`},
+			contains: []string{
+				"fn(format, args...)",
+				"Args   []interface{}",
+				"func (m *FakeLogger) SetOutput(a0 io.Writer, a1 bool) {",
+				"func (m *FakeLogger) Sync(a0 bool) error {",
+			},
+		},
+		{
+			name:          "receiver",
+			interfaceName: "WriterTo",
+			opts:          []MockOption{WithMockReceiver("w")},
+			methods:       []string{"WriteTo"},
+			imports:       []string{`"io"`, `"sync"`},
+			contains: []string{
+				"func (w0 *MockWriterTo) WriteTo(w io.Writer) (int64, error) {",
+			},
+		},
+		{
+			name:          "interface literal param",
+			interfaceName: "CloserUser",
+			methods:       []string{"Use"},
+			imports:       []string{`"sync"`},
+			contains: []string{
+				"UseFunc func(c interface{ Close() error }) error",
+				"func (m *MockCloserUser) Use(c interface{ Close() error }) error {",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				mock, err := p.GenerateMock(tt.interfaceName, tt.opts...)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if strings.Join(mock.Methods, ",") != strings.Join(tt.methods, ",") {
+					t.Errorf("Methods = %v, want %v", mock.Methods, tt.methods)
+				}
+				if strings.Join(mock.Imports, ",") != strings.Join(tt.imports, ",") {
+					t.Errorf("Imports = %v, want %v", mock.Imports, tt.imports)
+				}
+				for _, s := range tt.contains {
+					if !strings.Contains(mock.Code, s) {
+						t.Errorf("code not contains: %v\n%v", s, mock.Code)
+					}
+				}
+				checkMock(t, mock, tt.interfaceName)
+			},
+		)
+	}
+}
+
+// checkMock 和testdata一起做类型检查，并且检查mock实现了接口
+func checkMock(t *testing.T, mock *Mock, interfaceName string) {
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob("./testdata/*.go")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	files := make([]*ast.File, 0, len(paths)+1)
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		files = append(files, file)
+	}
+	file, err := parser.ParseFile(fset, "mock.go", mock.Code, 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	files = append(files, file)
+
+	config := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := config.Check("testdata", fset, files, nil)
+	if err != nil {
+		t.Fatalf("type check error: %v\n%v", err, mock.Code)
+	}
+	iface := pkg.Scope().Lookup(interfaceName).Type().Underlying().(*types.Interface)
+	tp := types.NewPointer(pkg.Scope().Lookup(mock.Name).Type())
+	if !types.Implements(tp, iface) {
+		t.Errorf("%v not implements %v", mock.Name, interfaceName)
+	}
+
+	// var _ I = &Mock{} 需要能通过编译
+	assert := fmt.Sprintf("package testdata\n\nvar _ %s = &%s{}\n", interfaceName, mock.Name)
+	file, err = parser.ParseFile(fset, "assert.go", assert, 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err = config.Check("testdata", fset, append(files, file), nil); err != nil {
+		t.Errorf("type check error: %v\n%v", err, mock.Code)
+	}
+}
@@ -0,0 +1,19 @@
+package astutil
+
+// ParserOption 解析器选项
+type ParserOption func(p *Parser)
+
+// WithTypeCheck 是否使用类型信息判断接口实现，默认开启。
+// 关闭或者没有类型信息时，使用函数签名的字符串匹配
+func WithTypeCheck(typeCheck bool) ParserOption {
+	return func(p *Parser) {
+		p.typeCheck = typeCheck
+	}
+}
+
+// WithDebug 是否打印调试信息，默认关闭，解析时忽略的问题见 ImplementReport.Warnings
+func WithDebug(debug bool) ParserOption {
+	return func(p *Parser) {
+		p.debug = debug
+	}
+}
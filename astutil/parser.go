@@ -5,8 +5,10 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
-	"golang.org/x/tools/go/packages"
+	"log"
 	"strings"
+
+	"golang.org/x/tools/go/packages"
 )
 
 // File holds a single parsed file and associated data.
@@ -27,10 +29,14 @@ type Package struct {
 
 // Parser 解析器
 type Parser struct {
-	debug   bool
-	pkg     *Package
-	p       *packages.Package
-	Imports []string
+	debug     bool
+	typeCheck bool
+	pkg       *Package
+	p         *packages.Package
+	Imports   []string
+
+	// warnings 解析时忽略的问题，每个 InterfaceParser 独立记录，见 ImplementReport.Warnings
+	warnings *[]string
 }
 
 // InterfaceParser 接口解析器
@@ -43,21 +49,44 @@ type InterfaceParser struct {
 	InterfaceFuncIdent map[string]*ast.Ident
 	TypeFuncIdent      map[string]*ast.Ident
 	FuncCodes          []*FuncToken
+
+	// interfaceFuncs 接口的函数，按定义的顺序排列
+	interfaceFuncs []*FuncToken
+	// valueFuncs T 的方法集，key是函数签名
+	valueFuncs map[string]*FuncToken
+	// pointerFuncs *T 的方法集，key是函数签名
+	pointerFuncs map[string]*FuncToken
+
+	// namedType 类型信息中的类型，没有类型信息时为nil
+	namedType types.Type
+	// iface 类型信息中的接口，没有类型信息时为nil
+	iface *types.Interface
 }
 
 // NewParser 初始化解析器
-func NewParser(pkg *packages.Package) *Parser {
+func NewParser(pkg *packages.Package, opts ...ParserOption) *Parser {
+	if err := CheckTypes(pkg); err != nil {
+		log.Printf("failed to check types of package: %v error: %v", pkg.ID, err.Error())
+	}
+	var defs map[*ast.Ident]types.Object
+	if pkg.TypesInfo != nil {
+		defs = pkg.TypesInfo.Defs
+	}
 	p := &Package{
 		name:  pkg.Name,
-		defs:  pkg.TypesInfo.Defs,
+		defs:  defs,
 		files: make([]*File, len(pkg.Syntax)),
 	}
 
 	parser := &Parser{
-		pkg:     p,
-		p:       pkg,
-		debug:   true,
-		Imports: make([]string, 0),
+		pkg:       p,
+		p:         pkg,
+		debug:     false,
+		typeCheck: true,
+		Imports:   make([]string, 0),
+	}
+	for _, opt := range opts {
+		opt(parser)
 	}
 
 	for i, file := range pkg.Syntax {
@@ -97,6 +126,21 @@ func (p Parser) Printf(format string, args ...interface{}) {
 	}
 }
 
+// warnf 记录解析时忽略的问题，相同的问题只记录一次
+func (p Parser) warnf(format string, args ...interface{}) {
+	p.Printf(format, args...)
+	if p.warnings == nil {
+		return
+	}
+	warning := fmt.Sprintf(format, args...)
+	for _, w := range *p.warnings {
+		if w == warning {
+			return
+		}
+	}
+	*p.warnings = append(*p.warnings, warning)
+}
+
 func (p Parser) findInterfaceType(interfaceName string) (interfaceType *ast.InterfaceType) {
 	for _, file := range p.p.Syntax {
 		p.Printf("find interface: %v in file: %v", interfaceName, file.Name.Name)
@@ -174,6 +218,8 @@ func (p Parser) ParseTypeAndInterface(typeName string, interfaceName string) (*I
 		return nil, fmt.Errorf("not found type: %v", typeName)
 	}
 
+	// p 是副本，每个 InterfaceParser 的问题单独记录
+	p.warnings = &[]string{}
 	ip := &InterfaceParser{
 		typeName:           typeName,
 		tp:                 tp,
@@ -192,35 +238,102 @@ func (p Parser) ParseTypeAndInterface(typeName string, interfaceName string) (*I
 		ip.Parser.parseFile(ip, file)
 	}
 
+	ip.valueFuncs, ip.pointerFuncs, _, err = ip.Parser.methodSets(typeName, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+	for signature, code := range ip.pointerFuncs {
+		ip.TypeFuncIdent[signature] = code.Ident
+	}
+
+	if p.typeCheck {
+		ip.namedType, ip.iface = p.lookupTypes(typeName, interfaceName)
+	}
+
 	return ip, nil
 }
 
-// IsTypeImplementsInterface 判断对应类型是否实现了接口
+// IsTypeImplementsInterface 判断对应类型 T 是否实现了接口，T 的方法集只包含值接收者的方法
 func (ip *InterfaceParser) IsTypeImplementsInterface() (bool, error) {
-	if len(ip.interfaceType.Methods.List) <= 0 {
+	return ip.Implements(false)
+}
+
+// IsPointerImplementsInterface 判断对应类型的指针 *T 是否实现了接口，
+// *T 的方法集包含值接收者和指针接收者的方法
+func (ip *InterfaceParser) IsPointerImplementsInterface() (bool, error) {
+	return ip.Implements(true)
+}
+
+// Implements 判断对应类型是否实现了接口，pointer为true时判断 *T，否则判断 T。
+// 有类型信息时使用 go/types 判断，否则使用函数签名的字符串匹配
+func (ip *InterfaceParser) Implements(pointer bool) (bool, error) {
+	if ip.namedType != nil && ip.iface != nil {
+		var tp types.Type = ip.namedType
+		if pointer {
+			tp = types.NewPointer(tp)
+		}
+		return types.Implements(tp, ip.iface), nil
+	}
+
+	if len(ip.InterfaceFuncIdent) <= 0 {
 		return true, nil
 	}
 
-	return ip.Parser.matchFuncs(ip), nil
+	funcs := ip.valueFuncs
+	if pointer {
+		funcs = ip.pointerFuncs
+	}
+	return ip.Parser.matchFuncs(ip, funcs), nil
 }
 
-func (p Parser) matchFuncs(ip *InterfaceParser) bool {
+func (p Parser) matchFuncs(ip *InterfaceParser, funcs map[string]*FuncToken) bool {
 	for s, ident := range ip.InterfaceFuncIdent { // loop interface funcs
-		a, ok := ip.TypeFuncIdent[s]
+		a, ok := funcs[s]
 		if !ok {
 			return false
 		}
-		p.Printf("found func: %v on type: %v of interface: %v", s, a, ident)
+		p.Printf("found func: %v on type: %v of interface: %v", s, a.Ident, ident)
 	}
 	return true
 }
 
-// ParseInterface 解析接口
+// lookupTypes 在类型信息中查找类型和接口，找不到时返回nil
+func (p Parser) lookupTypes(typeName string, interfaceName string) (types.Type, *types.Interface) {
+	typeObj := p.lookupTypeName(typeName)
+	ifaceObj := p.lookupTypeName(interfaceName)
+	if typeObj == nil || ifaceObj == nil {
+		return nil, nil
+	}
+	iface, ok := ifaceObj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, nil
+	}
+	return typeObj.Type(), iface
+}
+
+func (p Parser) lookupTypeName(name string) *types.TypeName {
+	if p.p.Types != nil {
+		if obj, ok := p.p.Types.Scope().Lookup(name).(*types.TypeName); ok {
+			return obj
+		}
+		return nil
+	}
+	for ident, obj := range p.pkg.defs {
+		typeName, ok := obj.(*types.TypeName)
+		if ok && ident.Name == name && typeName.Parent() == typeName.Pkg().Scope() {
+			return typeName
+		}
+	}
+	return nil
+}
+
+// ParseInterface 解析接口，包括内嵌接口的函数
 func (p Parser) ParseInterface(ip *InterfaceParser) error {
-	tokens, err := ParseInterfaceFunc(ip.interfaceType)
+	tokens, err := p.interfaceFuncs(ip.interfaceType, make(map[*ast.InterfaceType]bool))
 	if err != nil {
 		return err
 	}
+	ip.interfaceFuncs = tokens
 	for _, funcToken := range tokens {
 		ip.InterfaceFuncIdent[funcToken.BuildSignature()] = funcToken.Ident
 	}
@@ -245,27 +358,14 @@ func (p Parser) walkFunc(ip *InterfaceParser) func(node ast.Node) bool {
 }
 
 func (p Parser) funcDecl(ip *InterfaceParser, decl *ast.FuncDecl) bool {
-	recv := decl.Recv
-	if recv == nil || len(recv.List) == 0 {
+	if ip.tp.Name == nil {
 		return true
 	}
-	tp, ok := recv.List[0].Type.(*ast.Ident)
+	code, ok := p.methodOf(ip.tp.Name.Name, decl)
 	if !ok {
 		return true
 	}
-
-	if ip.tp.Name != nil && tp.Name != ip.tp.Name.Name {
-		return true
-	}
-	ft := decl.Type
-	code, err := BuildFuncCode(ft)
-	if err != nil {
-		return true
-	}
-	code.FuncName = decl.Name.Name
-	code.Ident = decl.Name
 	ip.FuncCodes = append(ip.FuncCodes, code)
-	ip.TypeFuncIdent[code.BuildSignature()] = code.Ident
 	return true
 }
 
@@ -32,13 +32,9 @@ func (i impl) Unmarshal(data []byte, v interface{}) error {
 }
 
 func TestIsTypeImplementsInterface(t *testing.T) {
-	cfg := &packages.Config{
-		Mode: packages.LoadSyntax,
-		// TODO: Need to think about constants in test files. Maybe write type_string_test.go
-		// in a separate pass? For later.
-		Tests: true,
-	}
-	pkgs, err := packages.Load(cfg, ".")
+	// TODO: Need to think about constants in test files. Maybe write type_string_test.go
+	// in a separate pass? For later.
+	pkgs, err := LoadPackages([]string{"."}, WithTests(true), WithCheckTypes(false))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -71,3 +67,128 @@ func TestIsTypeImplementsInterface(t *testing.T) {
 	t.Fail()
 
 }
+
+func TestInterfaceParser_Implements(t *testing.T) {
+	pkgs, err := ParsePackage([]string{"pattern=./testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkgs) == 0 {
+		t.Fatal("no package")
+	}
+	tests := []struct {
+		name     string
+		typeName string
+		value    bool
+		pointer  bool
+	}{
+		{
+			name:     "value and pointer receivers",
+			typeName: "Base",
+			value:    false,
+			pointer:  true,
+		},
+		{
+			name:     "pointer receivers",
+			typeName: "PointerImpl",
+			value:    false,
+			pointer:  true,
+		},
+		{
+			name:     "embedded struct",
+			typeName: "EmbedImpl",
+			value:    false,
+			pointer:  true,
+		},
+		{
+			name:     "embedded pointer",
+			typeName: "EmbedPointerImpl",
+			value:    true,
+			pointer:  true,
+		},
+		{
+			name:     "embedded interface",
+			typeName: "EmbedInterfaceImpl",
+			value:    true,
+			pointer:  true,
+		},
+		{
+			name:     "ambiguous embedded methods",
+			typeName: "AmbiguousImpl",
+			value:    false,
+			pointer:  false,
+		},
+		{
+			name:     "shallower embedded method",
+			typeName: "ShallowImpl",
+			value:    false,
+			pointer:  true,
+		},
+		{
+			name:     "not implemented",
+			typeName: "StructType",
+			value:    false,
+			pointer:  false,
+		},
+	}
+	// 没有类型信息时根据语法树计算方法集，结果应该和类型信息一致
+	for _, typeCheck := range []bool{true, false} {
+		p := NewParser(pkgs[0], WithTypeCheck(typeCheck))
+		for _, tt := range tests {
+			t.Run(
+				fmt.Sprintf("%v type check: %v", tt.name, typeCheck), func(t *testing.T) {
+					ip, err := p.ParseTypeAndInterface(tt.typeName, "ReadCloser")
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+					if got, _ := ip.IsTypeImplementsInterface(); got != tt.value {
+						t.Errorf("IsTypeImplementsInterface() = %v, want %v", got, tt.value)
+					}
+					if got, _ := ip.IsPointerImplementsInterface(); got != tt.pointer {
+						t.Errorf("IsPointerImplementsInterface() = %v, want %v", got, tt.pointer)
+					}
+				},
+			)
+		}
+	}
+}
+
+func TestInterfaceParser_ImplementsTypeCheck(t *testing.T) {
+	pkgs, err := ParsePackage([]string{"pattern=./testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkgs) == 0 {
+		t.Fatal("no package")
+	}
+	tests := []struct {
+		name      string
+		typeCheck bool
+		want      bool
+	}{
+		{
+			name:      "type check",
+			typeCheck: true,
+			want:      true,
+		},
+		{
+			name:      "signature match",
+			typeCheck: false,
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				p := NewParser(pkgs[0], WithTypeCheck(tt.typeCheck), WithDebug(false))
+				ip, err := p.ParseTypeAndInterface("AliasImpl", "ReadCloser")
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if got, _ := ip.IsTypeImplementsInterface(); got != tt.want {
+					t.Errorf("IsTypeImplementsInterface() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
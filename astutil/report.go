@@ -0,0 +1,238 @@
+package astutil
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// MethodStatus 接口方法的实现状态
+type MethodStatus string
+
+const (
+	// MethodImplemented 已实现
+	MethodImplemented MethodStatus = "implemented"
+
+	// MethodMissing 未实现
+	MethodMissing MethodStatus = "missing"
+
+	// MethodSignatureMismatch 存在同名方法，但是签名不一致
+	MethodSignatureMismatch MethodStatus = "signature_mismatch"
+
+	// MethodPointerReceiver 方法是指针接收者，只有 *T 实现了该方法
+	MethodPointerReceiver MethodStatus = "pointer_receiver"
+)
+
+// MethodReport 接口方法的实现情况
+type MethodReport struct {
+	// Name 方法名
+	Name string `json:"name" yaml:"name"`
+
+	// Status 实现状态
+	Status MethodStatus `json:"status" yaml:"status"`
+
+	// Expected 接口定义的签名
+	Expected string `json:"expected" yaml:"expected"`
+
+	// Actual 类型实现的签名，未实现时为空
+	Actual string `json:"actual" yaml:"actual"`
+
+	// ExpectedPos 接口定义方法的位置
+	ExpectedPos token.Position `json:"expected_pos" yaml:"expectedPos"`
+
+	// ActualPos 类型实现方法的位置，未实现时为空
+	ActualPos token.Position `json:"actual_pos" yaml:"actualPos"`
+}
+
+// ImplementReport 类型实现接口的报告
+type ImplementReport struct {
+	// TypeName 类型名，如果是指针则以*开头
+	TypeName string `json:"type_name" yaml:"typeName"`
+
+	// InterfaceName 接口名
+	InterfaceName string `json:"interface_name" yaml:"interfaceName"`
+
+	// Implemented 是否实现了接口
+	Implemented bool `json:"implemented" yaml:"implemented"`
+
+	// Methods 接口每个方法的实现情况，按方法名排序
+	Methods []*MethodReport `json:"methods" yaml:"methods"`
+
+	// Warnings 解析时忽略的问题，例如无法解析签名的方法、有歧义的内嵌方法，这些方法不在方法集中
+	Warnings []string `json:"warnings" yaml:"warnings"`
+}
+
+// String 打印可读的报告，用于CI输出
+func (r *ImplementReport) String() string {
+	sb := &strings.Builder{}
+	sb.WriteString(r.TypeName)
+	if r.Implemented {
+		sb.WriteString(" implements ")
+		sb.WriteString(r.InterfaceName)
+		writeWarnings(sb, r.Warnings)
+		return sb.String()
+	}
+	sb.WriteString(" does not implement ")
+	sb.WriteString(r.InterfaceName)
+	for _, method := range r.Methods {
+		switch method.Status {
+		case MethodMissing:
+			sb.WriteString("\n\tmissing method: ")
+			sb.WriteString(method.Expected)
+			writePos(sb, method.ExpectedPos)
+		case MethodSignatureMismatch:
+			sb.WriteString("\n\twrong signature of method: ")
+			sb.WriteString(method.Name)
+			sb.WriteString("\n\t\thave: ")
+			sb.WriteString(method.Actual)
+			writePos(sb, method.ActualPos)
+			sb.WriteString("\n\t\twant: ")
+			sb.WriteString(method.Expected)
+			writePos(sb, method.ExpectedPos)
+		case MethodPointerReceiver:
+			sb.WriteString("\n\tmethod has pointer receiver: ")
+			sb.WriteString(method.Actual)
+			writePos(sb, method.ActualPos)
+		}
+	}
+	writeWarnings(sb, r.Warnings)
+	return sb.String()
+}
+
+func writeWarnings(sb *strings.Builder, warnings []string) {
+	for _, warning := range warnings {
+		sb.WriteString("\n\twarning: ")
+		sb.WriteString(warning)
+	}
+}
+
+func writePos(sb *strings.Builder, pos token.Position) {
+	if !pos.IsValid() {
+		return
+	}
+	sb.WriteString(" (")
+	sb.WriteString(pos.String())
+	sb.WriteString(")")
+}
+
+// Report 生成类型实现接口的报告，pointer为true时检查 *T，否则检查 T。
+// 有类型信息时使用 go/types 检查，否则使用函数签名的字符串匹配
+func (ip *InterfaceParser) Report(pointer bool) (*ImplementReport, error) {
+	report := &ImplementReport{
+		TypeName:      ip.typeName,
+		InterfaceName: ip.interfaceName,
+		Implemented:   true,
+	}
+	if pointer {
+		report.TypeName = "*" + ip.typeName
+	}
+	if ip.Parser.warnings != nil && len(*ip.Parser.warnings) > 0 {
+		report.Warnings = append([]string(nil), *ip.Parser.warnings...)
+	}
+	if ip.namedType != nil && ip.iface != nil {
+		report.Methods = ip.typesReport(pointer)
+	} else {
+		report.Methods = ip.signatureReport(pointer)
+	}
+	sort.Slice(
+		report.Methods, func(i, j int) bool {
+			return report.Methods[i].Name < report.Methods[j].Name
+		},
+	)
+	for _, method := range report.Methods {
+		if method.Status != MethodImplemented {
+			report.Implemented = false
+		}
+	}
+	return report, nil
+}
+
+// signatureReport 使用函数签名检查每个接口方法
+func (ip *InterfaceParser) signatureReport(pointer bool) []*MethodReport {
+	funcs := ip.valueFuncs
+	if pointer {
+		funcs = ip.pointerFuncs
+	}
+	methods := make([]*MethodReport, 0, len(ip.interfaceFuncs))
+	for _, expected := range ip.interfaceFuncs {
+		method := &MethodReport{
+			Name:        expected.FuncName,
+			Status:      MethodMissing,
+			Expected:    expected.BuildSignature(),
+			ExpectedPos: ip.Parser.position(expected.Ident.Pos()),
+		}
+		methods = append(methods, method)
+
+		var actual *FuncToken
+		if found, ok := funcs[method.Expected]; ok {
+			actual, method.Status = found, MethodImplemented
+		} else if found := findFuncToken(funcs, expected.FuncName); found != nil {
+			actual, method.Status = found, MethodSignatureMismatch
+		} else if found := findFuncToken(ip.pointerFuncs, expected.FuncName); found != nil {
+			actual, method.Status = found, MethodPointerReceiver
+			if found.BuildSignature() != method.Expected {
+				method.Status = MethodSignatureMismatch
+			}
+		}
+		if actual != nil {
+			method.Actual = actual.BuildSignature()
+			method.ActualPos = ip.Parser.position(actual.Ident.Pos())
+		}
+	}
+	return methods
+}
+
+// typesReport 使用类型信息检查每个接口方法
+func (ip *InterfaceParser) typesReport(pointer bool) []*MethodReport {
+	var tp types.Type = ip.namedType
+	if pointer {
+		tp = types.NewPointer(tp)
+	}
+	set := types.NewMethodSet(tp)
+	pointerSet := types.NewMethodSet(types.NewPointer(ip.namedType))
+
+	methods := make([]*MethodReport, 0, ip.iface.NumMethods())
+	for i := 0; i < ip.iface.NumMethods(); i++ {
+		expected := ip.iface.Method(i)
+		method := &MethodReport{
+			Name:        expected.Name(),
+			Status:      MethodMissing,
+			Expected:    ip.Parser.TypesFuncToken(expected).BuildSignature(),
+			ExpectedPos: ip.Parser.position(expected.Pos()),
+		}
+		methods = append(methods, method)
+
+		var actual *types.Func
+		if sel := set.Lookup(expected.Pkg(), expected.Name()); sel != nil {
+			actual, method.Status = sel.Obj().(*types.Func), MethodImplemented
+		} else if sel := pointerSet.Lookup(expected.Pkg(), expected.Name()); sel != nil {
+			actual, method.Status = sel.Obj().(*types.Func), MethodPointerReceiver
+		}
+		if actual == nil {
+			continue
+		}
+		if !types.Identical(actual.Type(), expected.Type()) {
+			method.Status = MethodSignatureMismatch
+		}
+		method.Actual = ip.Parser.TypesFuncToken(actual).BuildSignature()
+		method.ActualPos = ip.Parser.position(actual.Pos())
+	}
+	return methods
+}
+
+func (p Parser) position(pos token.Pos) token.Position {
+	if p.p.Fset == nil || !pos.IsValid() {
+		return token.Position{}
+	}
+	return p.p.Fset.Position(pos)
+}
+
+func findFuncToken(funcs map[string]*FuncToken, name string) *FuncToken {
+	for _, code := range funcs {
+		if code.FuncName == name {
+			return code
+		}
+	}
+	return nil
+}
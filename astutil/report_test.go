@@ -0,0 +1,113 @@
+package astutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterfaceParser_Report(t *testing.T) {
+	pkgs, err := ParsePackage([]string{"pattern=./testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkgs) == 0 {
+		t.Fatal("no package")
+	}
+	tests := []struct {
+		name        string
+		typeName    string
+		pointer     bool
+		implemented bool
+		want        map[string]MethodStatus
+		warnings    []string
+	}{
+		{
+			name:        "missing",
+			typeName:    "StructType",
+			implemented: false,
+			want: map[string]MethodStatus{
+				"Close": MethodMissing,
+				"Read":  MethodMissing,
+			},
+		},
+		{
+			name:        "pointer receiver",
+			typeName:    "Base",
+			implemented: false,
+			want: map[string]MethodStatus{
+				"Close": MethodPointerReceiver,
+				"Read":  MethodImplemented,
+			},
+		},
+		{
+			name:        "pointer",
+			typeName:    "Base",
+			pointer:     true,
+			implemented: true,
+			want: map[string]MethodStatus{
+				"Close": MethodImplemented,
+				"Read":  MethodImplemented,
+			},
+		},
+		{
+			name:        "ambiguous",
+			typeName:    "AmbiguousImpl",
+			pointer:     true,
+			implemented: false,
+			want: map[string]MethodStatus{
+				"Close": MethodImplemented,
+				"Read":  MethodMissing,
+			},
+			warnings: []string{"ambiguous method: Read of type: AmbiguousImpl"},
+		},
+		{
+			name:        "signature mismatch",
+			typeName:    "MismatchImpl",
+			implemented: false,
+			want: map[string]MethodStatus{
+				"Close": MethodMissing,
+				"Read":  MethodSignatureMismatch,
+			},
+		},
+	}
+	for _, typeCheck := range []bool{true, false} {
+		p := NewParser(pkgs[0], WithTypeCheck(typeCheck), WithDebug(false))
+		for _, tt := range tests {
+			t.Run(
+				tt.name, func(t *testing.T) {
+					ip, err := p.ParseTypeAndInterface(tt.typeName, "ReadCloser")
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+					report, err := ip.Report(tt.pointer)
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+					if report.Implemented != tt.implemented {
+						t.Errorf("Implemented = %v, want %v\n%v", report.Implemented, tt.implemented, report)
+					}
+					if strings.Join(report.Warnings, ",") != strings.Join(tt.warnings, ",") {
+						t.Errorf("warnings = %v, want %v", report.Warnings, tt.warnings)
+					}
+					if len(report.Methods) != len(tt.want) {
+						t.Fatalf("methods = %v, want %v", len(report.Methods), len(tt.want))
+					}
+					for _, method := range report.Methods {
+						if method.Status != tt.want[method.Name] {
+							t.Errorf(
+								"type check: %v method: %v status = %v, want %v", typeCheck,
+								method.Name, method.Status, tt.want[method.Name],
+							)
+						}
+						if !method.ExpectedPos.IsValid() {
+							t.Errorf("method: %v expected position is invalid", method.Name)
+						}
+						if method.Status != MethodMissing && !method.ActualPos.IsValid() {
+							t.Errorf("method: %v actual position is invalid", method.Name)
+						}
+					}
+				},
+			)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package astutil
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+)
+
+// stubOptions 生成方法存根的选项
+type stubOptions struct {
+	receiverName string
+	pointer      bool
+	zeroReturn   bool
+}
+
+// StubOption 生成方法存根的选项
+type StubOption func(o *stubOptions)
+
+// WithStubReceiver 设置接收者的名称和是否是指针接收者，默认为类型名首字母小写的指针接收者
+func WithStubReceiver(name string, pointer bool) StubOption {
+	return func(o *stubOptions) {
+		o.receiverName = name
+		o.pointer = pointer
+	}
+}
+
+// WithZeroReturn 方法体返回零值，默认为 panic("implement me")
+func WithZeroReturn(zeroReturn bool) StubOption {
+	return func(o *stubOptions) {
+		o.zeroReturn = zeroReturn
+	}
+}
+
+// Stubs 生成的方法存根
+type Stubs struct {
+	// Methods 生成了存根的方法名
+	Methods []string `json:"methods" yaml:"methods"`
+
+	// Imports 方法签名需要的导入，例如 `"io"`、`stdio "io"`
+	Imports []string `json:"imports" yaml:"imports"`
+
+	// Code 格式化后的方法代码
+	Code string `json:"code" yaml:"code"`
+}
+
+// GenerateStubs 为类型还未实现的接口方法生成可编译的方法存根
+func (ip *InterfaceParser) GenerateStubs(opts ...StubOption) (*Stubs, error) {
+	o := &stubOptions{
+		receiverName: receiverName(ip.typeName),
+		pointer:      true,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	report, err := ip.Report(o.pointer)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make(map[string]*FuncToken, len(ip.interfaceFuncs))
+	for _, token := range ip.interfaceFuncs {
+		tokens[token.FuncName] = token
+	}
+	if ip.iface != nil {
+		for i := 0; i < ip.iface.NumMethods(); i++ {
+			method := ip.iface.Method(i)
+			if _, ok := tokens[method.Name()]; !ok {
+				tokens[method.Name()] = ip.Parser.TypesFuncToken(method)
+			}
+		}
+	}
+
+	missing := make([]*FuncToken, 0, len(report.Methods))
+	argNames := make(map[string]bool)
+	for _, method := range report.Methods {
+		if method.Status != MethodMissing {
+			continue
+		}
+		token, ok := tokens[method.Name]
+		if !ok {
+			return nil, fmt.Errorf("not found method: %v of interface: %v", method.Name, ip.interfaceName)
+		}
+		missing = append(missing, token)
+		for _, argName := range token.InArgNames {
+			argNames[argName] = true
+		}
+	}
+	// 接收者不能和参数重名
+	recvName := o.receiverName
+	for i := 0; argNames[recvName]; i++ {
+		recvName = fmt.Sprintf("%s%d", o.receiverName, i)
+	}
+
+	receiver := ip.typeName
+	// 泛型类型的接收者需要带上类型参数，例如 *List[T]
+	if ip.tp != nil && ip.tp.TypeParams != nil {
+		names := make([]string, 0, ip.tp.TypeParams.NumFields())
+		for _, field := range ip.tp.TypeParams.List {
+			for _, name := range field.Names {
+				names = append(names, name.Name)
+			}
+		}
+		receiver += "[" + strings.Join(names, ", ") + "]"
+	}
+	if o.pointer {
+		receiver = "*" + receiver
+	}
+	stubs := &Stubs{}
+	sb := &strings.Builder{}
+	typeCodes := make([]string, 0)
+	for _, token := range missing {
+		stubs.Methods = append(stubs.Methods, token.FuncName)
+		typeCodes = append(typeCodes, token.InTypes...)
+		typeCodes = append(typeCodes, token.OutTypes...)
+
+		sb.WriteString("\n")
+		fmt.Fprintf(sb, "// %s implements %s\n", token.FuncName, ip.interfaceName)
+		fmt.Fprintf(sb, "func (%s %s) %s {\n", recvName, receiver, token.FuncCode())
+		if o.zeroReturn {
+			if len(token.OutTypes) > 0 {
+				values := make([]string, 0, len(token.OutTypes))
+				for _, outType := range token.OutTypes {
+					values = append(values, ZeroValue(outType))
+				}
+				fmt.Fprintf(sb, "return %s\n", strings.Join(values, ", "))
+			}
+		} else {
+			sb.WriteString("panic(\"implement me\")\n")
+		}
+		sb.WriteString("}\n")
+	}
+
+	stubs.Imports, err = ip.Parser.UsedImports(typeCodes)
+	if err != nil {
+		return nil, err
+	}
+	code, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return nil, err
+	}
+	stubs.Code = string(code)
+	return stubs, nil
+}
+
+// FuncCode 生成函数名和签名的代码，例如 Read(p []byte) (int, error)、Map[K comparable, V any](m map[K]V) []V
+func (t FuncToken) FuncCode() string {
+	sb := &strings.Builder{}
+	sb.WriteString(t.FuncName)
+	if len(t.TypeParams) > 0 {
+		sb.WriteString("[")
+		sb.WriteString(strings.Join(t.TypeParams, ", "))
+		sb.WriteString("]")
+	}
+	sb.WriteString("(")
+	sb.WriteString(strings.Join(t.InArgAndTypes, ", "))
+	sb.WriteString(")")
+	switch len(t.OutTypes) {
+	case 0:
+	case 1:
+		sb.WriteString(" ")
+		sb.WriteString(t.OutTypes[0])
+	default:
+		sb.WriteString(" (")
+		sb.WriteString(strings.Join(t.OutTypes, ", "))
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// ZeroValue 根据类型代码生成零值的代码，无法确定的类型使用 *new(T)
+func ZeroValue(typeCode string) string {
+	switch typeCode {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64", "complex64", "complex128", "byte", "rune":
+		return "0"
+	case "error", "any", "interface{}", "unsafe.Pointer":
+		return "nil"
+	}
+	for _, prefix := range []string{"*", "[]", "map[", "chan ", "chan<- ", "<-chan ", "func(", "interface {"} {
+		if strings.HasPrefix(typeCode, prefix) {
+			return "nil"
+		}
+	}
+	return fmt.Sprintf("*new(%s)", typeCode)
+}
+
+// receiverName 默认的接收者名称，类型名首字母小写
+func receiverName(typeName string) string {
+	for _, r := range typeName {
+		return string(unicode.ToLower(r))
+	}
+	return "r"
+}
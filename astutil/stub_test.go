@@ -0,0 +1,191 @@
+package astutil
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInterfaceParser_GenerateStubs(t *testing.T) {
+	pkgs, err := ParsePackage([]string{"pattern=./testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkgs) == 0 {
+		t.Fatal("no package")
+	}
+	p := NewParser(pkgs[0], WithDebug(false))
+	tests := []struct {
+		name          string
+		typeName      string
+		interfaceName string
+		opts          []StubOption
+		methods       []string
+		imports       []string
+		contains      []string
+	}{
+		{
+			name:          "missing all",
+			typeName:      "StructType",
+			interfaceName: "ReadCloser",
+			methods:       []string{"Close", "Read"},
+			contains: []string{
+				"func (s *StructType) Close() error {",
+				"func (s *StructType) Read(p []byte) (int, error) {",
+				`panic("implement me")`,
+			},
+		},
+		{
+			name:          "value receiver",
+			typeName:      "StructType",
+			interfaceName: "ReadCloser",
+			opts:          []StubOption{WithStubReceiver("st", false), WithZeroReturn(true)},
+			methods:       []string{"Close", "Read"},
+			contains: []string{
+				"func (st StructType) Close() error {",
+				"return nil",
+				"return 0, nil",
+			},
+		},
+		{
+			name:          "pointer receiver exists",
+			typeName:      "Base",
+			interfaceName: "ReadCloser",
+			opts:          []StubOption{WithStubReceiver("b", false)},
+			methods:       nil,
+		},
+		{
+			name:          "imports",
+			typeName:      "StructType",
+			interfaceName: "WriterTo",
+			opts:          []StubOption{WithStubReceiver("w", true), WithZeroReturn(true)},
+			methods:       []string{"WriteTo"},
+			imports:       []string{`"io"`},
+			contains: []string{
+				"func (w0 *StructType) WriteTo(w io.Writer) (int64, error) {",
+				"return 0, nil",
+			},
+		},
+		{
+			name:          "interface literal param",
+			typeName:      "StructType",
+			interfaceName: "CloserUser",
+			methods:       []string{"Use"},
+			contains: []string{
+				"func (s *StructType) Use(c interface{ Close() error }) error {",
+			},
+		},
+		{
+			name:          "generic receiver",
+			typeName:      "Pair",
+			interfaceName: "ReadCloser",
+			methods:       []string{"Close", "Read"},
+			contains: []string{
+				"func (p0 *Pair[K, V]) Close() error {",
+				"func (p0 *Pair[K, V]) Read(p []byte) (int, error) {",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				ip, err := p.ParseTypeAndInterface(tt.typeName, tt.interfaceName)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				stubs, err := ip.GenerateStubs(tt.opts...)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				if strings.Join(stubs.Methods, ",") != strings.Join(tt.methods, ",") {
+					t.Errorf("methods = %v, want %v", stubs.Methods, tt.methods)
+				}
+				if strings.Join(stubs.Imports, ",") != strings.Join(tt.imports, ",") {
+					t.Errorf("imports = %v, want %v", stubs.Imports, tt.imports)
+				}
+				for _, code := range tt.contains {
+					if !strings.Contains(stubs.Code, code) {
+						t.Errorf("code: %v not contains: %v", stubs.Code, code)
+					}
+				}
+				checkStubs(t, stubs, tt.typeName, tt.interfaceName)
+			},
+		)
+	}
+}
+
+// checkStubs 和 testdata 一起对生成的存根进行类型检查，并且 *T 可以赋值给接口
+func checkStubs(t *testing.T, stubs *Stubs, typeName string, interfaceName string) {
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob("./testdata/*.go")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	files := make([]*ast.File, 0, len(paths)+1)
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		files = append(files, file)
+	}
+	src := "package testdata\n"
+	for _, is := range stubs.Imports {
+		src += "import " + is + "\n"
+	}
+	file, err := parser.ParseFile(fset, "stub.go", src+stubs.Code, 0)
+	if err != nil {
+		t.Fatalf("failed to parse code: %v error: %v", stubs.Code, err.Error())
+	}
+	files = append(files, file)
+
+	config := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := config.Check("testdata", fset, files, nil)
+	if err != nil {
+		t.Fatalf("type check error: %v\n%v", err, stubs.Code)
+	}
+	// var _ I = (*T)(nil) 需要能通过编译，泛型类型使用 int 实例化
+	instance := typeName
+	if n := pkg.Scope().Lookup(typeName).Type().(*types.Named).TypeParams().Len(); n > 0 {
+		instance += "[" + strings.TrimSuffix(strings.Repeat("int, ", n), ", ") + "]"
+	}
+	assert := fmt.Sprintf("package testdata\n\nvar _ %s = (*%s)(nil)\n", interfaceName, instance)
+	file, err = parser.ParseFile(fset, "assert.go", assert, 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err = config.Check("testdata", fset, append(files, file), nil); err != nil {
+		t.Errorf("%v not implements %v: %v\n%v", typeName, interfaceName, err, stubs.Code)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	tests := []struct {
+		typeCode string
+		want     string
+	}{
+		{typeCode: "string", want: `""`},
+		{typeCode: "int64", want: "0"},
+		{typeCode: "bool", want: "false"},
+		{typeCode: "error", want: "nil"},
+		{typeCode: "*packages.Package", want: "nil"},
+		{typeCode: "map[string]int", want: "nil"},
+		{typeCode: "<-chan int", want: "nil"},
+		{typeCode: "packages.Config", want: "*new(packages.Config)"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.typeCode, func(t *testing.T) {
+				if got := ZeroValue(tt.typeCode); got != tt.want {
+					t.Errorf("ZeroValue() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,7 @@
+package broken
+
+// Broken has a type error
+var Broken int = "broken"
+
+// Undefined refers to an undefined type
+var Undefined NotExists
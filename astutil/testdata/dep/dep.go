@@ -0,0 +1,4 @@
+package dep
+
+// Dep dependency of testdata, used by overlay tests
+type Dep struct{}
@@ -0,0 +1,122 @@
+package testdata
+
+import "io"
+
+// Reader reader
+type Reader interface {
+	Read(p []byte) (n int, err error)
+}
+
+// ReadCloser interface with embedded interfaces
+type ReadCloser interface {
+	Reader
+	io.Closer
+}
+
+// Base base type with value and pointer receivers
+type Base struct {
+}
+
+// Read value receiver
+func (b Base) Read(p []byte) (int, error) {
+	return 0, nil
+}
+
+// Close pointer receiver
+func (b *Base) Close() error {
+	return nil
+}
+
+// PointerImpl implements ReadCloser by pointer receivers
+type PointerImpl struct {
+}
+
+// Read pointer receiver
+func (p *PointerImpl) Read(data []byte) (int, error) {
+	return 0, nil
+}
+
+// Close pointer receiver
+func (p *PointerImpl) Close() error {
+	return nil
+}
+
+// EmbedImpl embeds Base
+type EmbedImpl struct {
+	Base
+}
+
+// EmbedPointerImpl embeds *Base
+type EmbedPointerImpl struct {
+	*Base
+}
+
+// OtherReader another type with the same Read method as Base
+type OtherReader struct {
+}
+
+// Read value receiver
+func (o OtherReader) Read(p []byte) (int, error) {
+	return 0, nil
+}
+
+// AmbiguousImpl embeds Base and OtherReader, Read is ambiguous and not promoted
+type AmbiguousImpl struct {
+	Base
+	OtherReader
+}
+
+// ShallowImpl embeds EmbedImpl and OtherReader, the shallower Read of OtherReader is promoted
+type ShallowImpl struct {
+	EmbedImpl
+	OtherReader
+}
+
+// EmbedInterfaceImpl embeds io.ReadCloser
+type EmbedInterfaceImpl struct {
+	io.ReadCloser
+}
+
+// Bytes alias of []byte
+type Bytes = []byte
+
+// AliasImpl implements ReadCloser with aliased and named types
+type AliasImpl struct {
+}
+
+// Read the param type is an alias
+func (a AliasImpl) Read(data Bytes) (n int, err error) {
+	return 0, nil
+}
+
+// Close the result is named
+func (a AliasImpl) Close() (err error) {
+	return nil
+}
+
+// MismatchImpl implements ReadCloser with a wrong signature
+type MismatchImpl struct {
+}
+
+// Read the param type is wrong
+func (m MismatchImpl) Read(data string) error {
+	return nil
+}
+
+// WriterTo interface with types of other packages
+type WriterTo interface {
+	WriteTo(w io.Writer) (n int64, err error)
+}
+
+// CloserUser interface with an interface literal param
+type CloserUser interface {
+	Use(c interface{ Close() error }) error
+}
+
+// Logger interface with variadic, unnamed and blank params
+type Logger interface {
+	Logf(format string, args ...interface{})
+	Level() int
+	SetOutput(io.Writer, bool)
+	Sync(_ bool) error
+}
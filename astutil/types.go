@@ -0,0 +1,92 @@
+package astutil
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"runtime"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// checkMu 依赖的包可能被多个包共享，类型检查需要串行
+var checkMu sync.Mutex
+
+// CheckTypes 如果包没有类型信息，则根据源码对包进行类型检查，补全 Types 和 TypesInfo。
+// 导入的包使用 pkg.Imports 中加载的包，没有类型信息的依赖会先进行类型检查，所有包共享同一套类型对象。
+// 类型检查的错误会追加到 pkg.Errors，并返回第一个错误，此时类型信息可能是不完整的
+func CheckTypes(pkg *packages.Package) error {
+	checkMu.Lock()
+	defer checkMu.Unlock()
+	return checkPackage(pkg)
+}
+
+// checkPackage 先检查依赖再检查包本身，调用方需要持有 checkMu
+func checkPackage(pkg *packages.Package) error {
+	if pkg.Types != nil && pkg.TypesInfo != nil {
+		return nil
+	}
+	if pkg.Fset == nil {
+		return errors.New("package's file set is nil: " + pkg.ID)
+	}
+	for _, ipkg := range pkg.Imports {
+		if ipkg.Fset == nil {
+			ipkg.Fset = pkg.Fset
+		}
+		// 依赖的错误记录在依赖的 Errors 中
+		_ = checkPackage(ipkg)
+	}
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+	}
+	var firstErr error
+	conf := &types.Config{
+		Importer: importerFunc(
+			func(path string) (*types.Package, error) {
+				if path == "unsafe" {
+					return types.Unsafe, nil
+				}
+				ipkg, ok := pkg.Imports[path]
+				if !ok || ipkg.Types == nil {
+					return nil, fmt.Errorf("no types of import: %v package: %v", path, pkg.ID)
+				}
+				return ipkg.Types, nil
+			},
+		),
+		Sizes: types.SizesFor("gc", runtime.GOARCH),
+		Error: func(err error) {
+			if firstErr == nil {
+				firstErr = err
+			}
+			pkgErr := packages.Error{
+				Msg:  err.Error(),
+				Kind: packages.TypeError,
+			}
+			if typeErr, ok := err.(types.Error); ok {
+				pkgErr.Pos = typeErr.Fset.Position(typeErr.Pos).String()
+				pkgErr.Msg = typeErr.Msg
+			}
+			pkg.Errors = append(pkg.Errors, pkgErr)
+		},
+	}
+	tp, _ := conf.Check(pkg.PkgPath, pkg.Fset, pkg.Syntax, info)
+	pkg.Types = tp
+	pkg.TypesInfo = info
+	pkg.TypesSizes = conf.Sizes
+	pkg.IllTyped = firstErr != nil
+	return firstErr
+}
+
+// importerFunc 函数实现的 types.Importer
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
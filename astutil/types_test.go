@@ -0,0 +1,29 @@
+package astutil
+
+import (
+	"testing"
+)
+
+func TestCheckTypes(t *testing.T) {
+	pkgs, err := ParsePackage([]string{"pattern=./testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkgs) == 0 {
+		t.Fatal("no package")
+	}
+	pkg := pkgs[0]
+	if err := CheckTypes(pkg); err != nil {
+		t.Fatal(err.Error())
+	}
+	if pkg.Types == nil || pkg.TypesInfo == nil {
+		t.Fatal("types is nil")
+	}
+	obj := pkg.Types.Scope().Lookup("StructType")
+	if obj == nil {
+		t.Fatal("not found type: StructType")
+	}
+	if got := obj.Type().Underlying().String(); got != "struct{}" {
+		t.Errorf("underlying = %v, want struct{}", got)
+	}
+}
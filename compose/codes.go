@@ -0,0 +1,111 @@
+package compose
+
+import (
+	"strings"
+
+	"github.com/pjoc-team/ast/scan"
+)
+
+// codesOptions 新建 Codes 的选项
+type codesOptions struct {
+	annotations []string
+}
+
+// CodesOption 新建 Codes 的选项
+type CodesOption func(o *codesOptions)
+
+// WithAnnotations 只提供有以这些前缀开头的注解的函数和类型，
+// 注解需要在扫描时通过 scan.WithAnnotationPrefixes 识别。
+// 有注解的类型的方法也会提供，没有注解的类型的字段不会提供
+func WithAnnotations(prefixes ...string) CodesOption {
+	return func(o *codesOptions) {
+		o.annotations = append(o.annotations, prefixes...)
+	}
+}
+
+// NewCodes 新建已提供的对象
+func NewCodes(pkgs []*scan.Pkg, predefines []*Object, opts ...CodesOption) *Codes {
+	o := &codesOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	codes := &Codes{
+		Packages:   pkgs,
+		Predefines: predefines,
+	}
+	if len(o.annotations) > 0 {
+		codes.Packages = make([]*scan.Pkg, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			codes.Packages = append(codes.Packages, annotatedPkg(pkg, o.annotations))
+		}
+	}
+	codes.Module = scan.NewModule(codes.Packages)
+	return codes
+}
+
+// NewModuleCodes 使用 scan.ScanPatterns 扫描的多个包新建已提供的对象
+func NewModuleCodes(module *scan.Module, predefines []*Object, opts ...CodesOption) *Codes {
+	return NewCodes(module.Packages, predefines, opts...)
+}
+
+// annotatedPkg 复制包，只保留有注解的函数和类型，以及有注解的类型的方法
+func annotatedPkg(pkg *scan.Pkg, prefixes []string) *scan.Pkg {
+	annotated := make(map[string]bool)
+	excluded := make([]string, 0)
+	rs := *pkg
+	rs.Files = make([]*scan.File, 0, len(pkg.Files))
+	for _, file := range pkg.Files {
+		f := *file
+		f.Types = make([]*scan.Type, 0, len(file.Types))
+		for _, t := range file.Types {
+			if !t.HasAnnotation(prefixes...) {
+				excluded = append(excluded, t.Path.String())
+				continue
+			}
+			annotated[t.Name] = true
+			f.Types = append(f.Types, t)
+		}
+		rs.Files = append(rs.Files, &f)
+	}
+	for _, f := range rs.Files {
+		funcs := f.Funcs
+		f.Funcs = make([]*scan.Func, 0, len(funcs))
+		for _, function := range funcs {
+			if !function.HasAnnotation(prefixes...) && !receiverAnnotated(function, annotated) {
+				excluded = append(excluded, function.Path.String())
+				continue
+			}
+			f.Funcs = append(f.Funcs, function)
+		}
+	}
+
+	rs.PathAndTypes = make(map[string]interface{}, len(pkg.PathAndTypes))
+	for path, obj := range pkg.PathAndTypes {
+		if !isExcludedPath(path, excluded) {
+			rs.PathAndTypes[path] = obj
+		}
+	}
+	return &rs
+}
+
+// receiverAnnotated 方法的接收者类型是否有注解
+func receiverAnnotated(function *scan.Func, annotated map[string]bool) bool {
+	if function.Receiver == nil {
+		return false
+	}
+	name := strings.TrimPrefix(function.Receiver.Type, "*")
+	if index := strings.Index(name, "["); index >= 0 {
+		name = name[:index]
+	}
+	return annotated[name]
+}
+
+// isExcludedPath 路径是否是被屏蔽的对象，或者是被屏蔽的对象的子路径，例如类型的字段
+func isExcludedPath(path string, excluded []string) bool {
+	for _, e := range excluded {
+		if path == e || strings.HasPrefix(path, e+" -> ") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package compose
+
+import (
+	"testing"
+
+	"github.com/pjoc-team/ast/astutil"
+	"github.com/pjoc-team/ast/scan"
+)
+
+func TestNewCodes(t *testing.T) {
+	packages, err := astutil.ParsePackage([]string{"pattern=../scan/testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(packages) == 0 {
+		t.Fatal("no package")
+	}
+	pkg, err := scan.ScanPkg(packages[0], scan.WithOnlyExported(true), scan.WithAnnotationPrefixes("@compose"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	file := "annotated.go"
+	tests := []struct {
+		name  string
+		opts  []CodesOption
+		path  scan.Path
+		found bool
+	}{
+		{
+			name:  "all",
+			path:  scan.Path{pkg.ID, file, "NotApproved"},
+			found: true,
+		},
+		{
+			name:  "all fields",
+			path:  scan.Path{pkg.ID, "docs.go", "Documented", "Name"},
+			found: true,
+		},
+		{
+			name:  "annotated func",
+			opts:  []CodesOption{WithAnnotations("@compose")},
+			path:  scan.Path{pkg.ID, file, "Approved"},
+			found: true,
+		},
+		{
+			name:  "not annotated func",
+			opts:  []CodesOption{WithAnnotations("@compose")},
+			path:  scan.Path{pkg.ID, file, "NotApproved"},
+			found: false,
+		},
+		{
+			name:  "method of annotated type",
+			opts:  []CodesOption{WithAnnotations("@compose")},
+			path:  scan.Path{pkg.ID, file, "*Service.Call"},
+			found: true,
+		},
+		{
+			name:  "field of annotated type",
+			opts:  []CodesOption{WithAnnotations("@compose")},
+			path:  scan.Path{pkg.ID, file, "Service", "Name"},
+			found: true,
+		},
+		{
+			name:  "field of not annotated type",
+			opts:  []CodesOption{WithAnnotations("@compose")},
+			path:  scan.Path{pkg.ID, "docs.go", "Documented", "Name"},
+			found: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				codes := NewCodes([]*scan.Pkg{pkg}, nil, tt.opts...)
+				if _, ok := codes.Packages[0].FindPath(tt.path); ok != tt.found {
+					t.Errorf("FindPath(%v) = %v, want %v", tt.path, ok, tt.found)
+				}
+			},
+		)
+	}
+	if _, ok := pkg.FindPath(scan.Path{pkg.ID, file, "NotApproved"}); !ok {
+		t.Errorf("the scanned package should not be changed")
+	}
+}
+
+func TestNewModuleCodes(t *testing.T) {
+	module, err := scan.ScanPatterns([]string{"../scan/testdata/module/..."}, scan.WithOnlyExported(true))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	codes := NewModuleCodes(module, nil)
+	if len(codes.Packages) != 2 || codes.Module == nil {
+		t.Fatalf("codes = %#v", codes)
+	}
+	b := &ActionBuilder{Builder: NewBuilder(codes)}
+	svc, ok := module.Lookup("github.com/pjoc-team/ast/scan/testdata/module/service", "Service")
+	if !ok {
+		t.Fatal("not found symbol: Service")
+	}
+	owner := svc.(*scan.Type).Fields[1]
+	found, err := b.findPath(owner.TypePath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if user, ok := found.(*scan.Type); !ok || user.Name != "User" {
+		t.Errorf("findPath(%v) = %#v", owner.TypePath, found)
+	}
+	if found, _ := b.findPath(scan.Path{"not/found", "a.go", "A"}); found != nil {
+		t.Errorf("findPath() = %#v, want nil", found)
+	}
+
+	// 新建后添加的包不在索引中
+	pkg, err := scan.ScanSource(
+		"example.com/added", map[string]string{"added.go": "package added\n\n// Added type\ntype Added struct{}\n"},
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	codes.Packages = append(codes.Packages, pkg)
+	addedPath := scan.Path{pkg.ID, "added.go", "Added"}
+	found, err = b.findPath(addedPath)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if added, ok := found.(*scan.Type); !ok || added.Name != "Added" {
+		t.Errorf("findPath(%v) = %#v", addedPath, found)
+	}
+}
@@ -92,6 +92,9 @@ type Codes struct {
 	// Packages 提供的代码包
 	Packages []*scan.Pkg
 
+	// Module 提供的代码包的索引，为nil时逐个包查找
+	Module *scan.Module
+
 	// Predefines 预定义对象集合，可以被引用
 	Predefines []*Object
 }
@@ -656,6 +659,12 @@ func (b *ActionBuilder) findPath(path scan.Path) (interface{}, error) {
 	if path == nil {
 		return nil, nil
 	}
+	if module := b.Builder.Codes.Module; module != nil {
+		if found, ok := module.FindPath(path); ok {
+			return found, nil
+		}
+	}
+	// 索引建立后可能又添加了包，找不到时再遍历所有包
 	for _, pkg := range b.Builder.Codes.Packages {
 		found, b2 := pkg.FindPath(path)
 		if b2 {
@@ -85,7 +85,12 @@ func (b *ActionBuilder) findField(obj *Object, name string) (*Object, error) {
 			return nil, err
 		}
 		if ts, ok := (strct).(*scan.Type); strct != nil && ok && ts.Type == scan.TypeStruct {
-			for _, field := range ts.Fields {
+			for _, ambiguous := range ts.AmbiguousFields {
+				if ambiguous == name {
+					return nil, fmt.Errorf("ambiguous selector: %v.%v", obj.Name, name)
+				}
+			}
+			for _, field := range structFields(ts) {
 				if field.Name == name {
 					obj := &Object{
 						Name:  name,
@@ -102,6 +107,18 @@ func (b *ActionBuilder) findField(obj *Object, name string) (*Object, error) {
 	return nil, nil
 }
 
+// structFields 结构体可以访问的字段，包括内嵌类型提升的字段
+func structFields(ts *scan.Type) []*scan.Field {
+	if ts.FieldSet == nil {
+		return ts.Fields
+	}
+	fields := make([]*scan.Field, 0, len(ts.FieldSet))
+	for _, field := range ts.FieldSet {
+		fields = append(fields, field.Field)
+	}
+	return fields
+}
+
 func (b *ActionBuilder) requiredObjectName(objectName string) (*Object, bool) {
 	if object, ok := b.CodeContext.Vars[objectName]; ok {
 		return object, ok
@@ -151,6 +168,28 @@ func finalField(arg string) string {
 
 // checkArgsType 检查两个arg的参数类型是否匹配，如果一个包含了package，另外一个没包含package，则默认按相等处理
 func checkArgsType(object string, another string) bool {
+	objectElem, objectDir, objectIsChan := scan.ParseChanType(object)
+	anotherElem, anotherDir, anotherIsChan := scan.ParseChanType(another)
+	if objectIsChan || anotherIsChan {
+		if !objectIsChan || !anotherIsChan {
+			return false
+		}
+		// 双向chan可以赋值给单向chan，反之则不行
+		if objectDir != anotherDir && objectDir != scan.ChanBoth {
+			return false
+		}
+		return identicalArgsType(objectElem, anotherElem)
+	}
+	return identicalArgsType(object, another)
+}
+
+// identicalArgsType 检查两个arg的参数类型是否一致，chan的元素类型必须一致，方向也不能转换
+func identicalArgsType(object string, another string) bool {
+	objectElem, objectDir, objectIsChan := scan.ParseChanType(object)
+	anotherElem, anotherDir, anotherIsChan := scan.ParseChanType(another)
+	if objectIsChan || anotherIsChan {
+		return objectIsChan && anotherIsChan && objectDir == anotherDir && identicalArgsType(objectElem, anotherElem)
+	}
 	if depth(object) == depth(another) {
 		return object == another
 	}
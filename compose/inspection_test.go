@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/pjoc-team/ast/astutil"
 	"github.com/pjoc-team/ast/scan"
 )
 
@@ -433,6 +434,62 @@ func Test_checkArgsType(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "t5",
+			args: args{
+				object:  "chan foo.bar",
+				another: "<-chan bar",
+			},
+			want: true,
+		},
+		{
+			name: "t6",
+			args: args{
+				object:  "<-chan string",
+				another: "chan string",
+			},
+			want: false,
+		},
+		{
+			name: "t7",
+			args: args{
+				object:  "chan<- string",
+				another: "chan<- string",
+			},
+			want: true,
+		},
+		{
+			name: "t8",
+			args: args{
+				object:  "chan foo.bar",
+				another: "bar",
+			},
+			want: false,
+		},
+		{
+			name: "t9",
+			args: args{
+				object:  "chan (<-chan string)",
+				another: "chan<- <-chan string",
+			},
+			want: true,
+		},
+		{
+			name: "t10",
+			args: args{
+				object:  "chan chan int",
+				another: "chan (<-chan int)",
+			},
+			want: false,
+		},
+		{
+			name: "t11",
+			args: args{
+				object:  "chan chan foo.bar",
+				another: "<-chan chan bar",
+			},
+			want: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(
@@ -443,4 +500,70 @@ func Test_checkArgsType(t *testing.T) {
 			},
 		)
 	}
-}
\ No newline at end of file
+}
+func TestActionBuilder_findFieldPromoted(t *testing.T) {
+	packages, err := astutil.ParsePackage([]string{"pattern=../scan/testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(packages) == 0 {
+		t.Fatal("no package")
+	}
+	pkg, err := scan.ScanPkg(packages[0], scan.WithOnlyExported(true))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	b := &ActionBuilder{
+		Builder: NewBuilder(NewCodes([]*scan.Pkg{pkg}, nil)),
+		CodeContext: &CodeContext{
+			Vars: map[string]*Object{
+				"req": {
+					Name: "req",
+					Type: "Request",
+					Path: scan.Path{pkg.ID, "embed.go", "Request"},
+				},
+			},
+			Predefines: map[string]*Object{},
+		},
+	}
+	tests := []struct {
+		name     string
+		typ      string
+		path     scan.Path
+		wantErr  bool
+		notFound bool
+	}{
+		{name: "req.ID", typ: "string", path: scan.Path{pkg.ID, "embed.go", "Request", "ID"}},
+		{name: "req.Base", typ: "*Base", path: scan.Path{pkg.ID, "embed.go", "Request", "Base"}},
+		{name: "req.At", typ: "int64", path: scan.Path{pkg.ID, "embed.go", "Audit", "At"}},
+		{name: "req.Line", typ: "int"},
+		{name: "req.Name", wantErr: true},
+		{name: "req.NotFound", notFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				objects, err := b.findObject(tt.name)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("findObject() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					return
+				}
+				if len(objects) != 2 {
+					t.Fatalf("findObject() = %v", objects)
+				}
+				field := objects[1]
+				if tt.notFound {
+					if field != nil {
+						t.Errorf("findObject() = %v, want nil", field)
+					}
+					return
+				}
+				if field == nil || field.Type != tt.typ || field.Path.String() != tt.path.String() {
+					t.Errorf("findObject() = %v, want type: %v path: %v", field, tt.typ, tt.path)
+				}
+			},
+		)
+	}
+}
@@ -1,10 +1,15 @@
 module github.com/pjoc-team/ast
 
-go 1.15
+go 1.18
 
 require (
 	github.com/blademainer/commons v0.0.15-0.20201029061424-ceb0b7537a27
 	github.com/iancoleman/strcase v0.0.0-20191112232945-16388991a334
-	github.com/pjoc-team/tracing v0.0.0-20210526041458-a4e0b42cb369 // indirect
 	golang.org/x/tools v0.1.5
 )
+
+require (
+	golang.org/x/mod v0.4.2 // indirect
+	golang.org/x/sys v0.0.0-20210510120138-977fb7262007 // indirect
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
+)
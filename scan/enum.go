@@ -0,0 +1,86 @@
+package scan
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GenerateEnum 为当前包的枚举类型生成完整的go文件，包括：
+//   - String 方法，返回常量名，值相同的常量使用第一个声明的常量名
+//   - Parse<Type> 函数，根据常量名解析枚举值
+//   - MarshalJSON、UnmarshalJSON 方法，JSON中使用常量名
+func (p *Pkg) GenerateEnum(typeName string) (string, error) {
+	t := p.findType(typeName)
+	if t == nil {
+		return "", fmt.Errorf("not found type: %v package: %v", typeName, p.Name)
+	}
+	if len(t.Enum) == 0 {
+		return "", fmt.Errorf("type: %v has no enum members", typeName)
+	}
+	if t.Alias || len(t.TypeParams) > 0 {
+		return "", fmt.Errorf("type: %v can not be an enum", typeName)
+	}
+
+	r, _ := utf8.DecodeRuneInString(typeName)
+	recv := string(unicode.ToLower(r))
+
+	sb := &strings.Builder{}
+	sb.WriteString("// Code generated by github.com/pjoc-team/ast/scan. DO NOT EDIT.\n\n")
+	fmt.Fprintf(sb, "package %s\n\n", p.Name)
+	sb.WriteString("import (\n\"encoding/json\"\n\"fmt\"\n)\n\n")
+
+	// switch的case不能有重复的常量值
+	seen := make(map[string]bool)
+	fmt.Fprintf(sb, "// String returns the name of %s\n", typeName)
+	fmt.Fprintf(sb, "func (%s %s) String() string {\n", recv, typeName)
+	fmt.Fprintf(sb, "switch %s {\n", recv)
+	for _, member := range t.Enum {
+		key := member.exact
+		if key == "" {
+			key = member.Value
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		fmt.Fprintf(sb, "case %s:\nreturn %q\n", member.Name, member.Name)
+	}
+	sb.WriteString("}\n")
+	fmt.Fprintf(sb, "return fmt.Sprintf(\"%s(%%v)\", %s(%s))\n", typeName, t.Underlying, recv)
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(sb, "// Parse%s parses %s from its name\n", typeName, typeName)
+	fmt.Fprintf(sb, "func Parse%s(name string) (%s, error) {\n", typeName, typeName)
+	sb.WriteString("switch name {\n")
+	for _, member := range t.Enum {
+		fmt.Fprintf(sb, "case %q:\nreturn %s, nil\n", member.Name, member.Name)
+	}
+	sb.WriteString("}\n")
+	fmt.Fprintf(sb, "var zero %s\n", typeName)
+	fmt.Fprintf(sb, "return zero, fmt.Errorf(\"unknown %s: %%q\", name)\n", typeName)
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(sb, "// MarshalJSON marshals %s as its name\n", typeName)
+	fmt.Fprintf(sb, "func (%s %s) MarshalJSON() ([]byte, error) {\n", recv, typeName)
+	fmt.Fprintf(sb, "return json.Marshal(%s.String())\n", recv)
+	sb.WriteString("}\n\n")
+
+	fmt.Fprintf(sb, "// UnmarshalJSON unmarshals %s from its name\n", typeName)
+	fmt.Fprintf(sb, "func (%s *%s) UnmarshalJSON(data []byte) error {\n", recv, typeName)
+	sb.WriteString("var name string\n")
+	sb.WriteString("if err := json.Unmarshal(data, &name); err != nil {\nreturn err\n}\n")
+	fmt.Fprintf(sb, "parsed, err := Parse%s(name)\n", typeName)
+	sb.WriteString("if err != nil {\nreturn err\n}\n")
+	fmt.Fprintf(sb, "*%s = parsed\n", recv)
+	sb.WriteString("return nil\n")
+	sb.WriteString("}\n")
+
+	code, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return "", err
+	}
+	return string(code), nil
+}
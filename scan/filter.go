@@ -2,8 +2,268 @@ package scan
 
 import (
 	"context"
-	"go/ast"
+	"path/filepath"
+	"regexp"
 )
 
-// Filter 过滤器。如果通过则返回true，否则屏蔽请返回false
-type Filter func(ctx context.Context, expr ast.Expr) bool
+// EntityKind 被过滤的实体类型
+type EntityKind string
+
+const (
+	// EntityFile 源文件，对应 *File
+	EntityFile EntityKind = "file"
+
+	// EntityType 类型，对应 *Type
+	EntityType EntityKind = "type"
+
+	// EntityFunc 函数、方法以及接口定义的方法，对应 *Func
+	EntityFunc EntityKind = "func"
+
+	// EntityField 结构体的字段，对应 *Field
+	EntityField EntityKind = "field"
+
+	// EntityValue 变量和常量，对应 *Value
+	EntityValue EntityKind = "value"
+)
+
+// Entity 被过滤的实体
+type Entity struct {
+	// Kind 实体类型
+	Kind EntityKind
+
+	// Name 实体名称，文件为文件名，方法为方法名
+	Name string
+
+	// Path 查找该实体的路径，一般是从 Pkg -> File -> Func/Struct -> Field
+	Path Path
+
+	// Object 实体对象，例如 *File、*Type、*Func、*Field、*Value
+	Object interface{}
+}
+
+// Filter 过滤器。如果通过则返回true，否则屏蔽请返回false。
+// 屏蔽了文件或者类型时，文件中的实体或者类型的字段、方法也会被屏蔽
+type Filter func(ctx context.Context, entity *Entity) bool
+
+// And 所有过滤器都通过时才通过
+func And(filters ...Filter) Filter {
+	return func(ctx context.Context, entity *Entity) bool {
+		for _, filter := range filters {
+			if !filter(ctx, entity) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or 任意一个过滤器通过时就通过
+func Or(filters ...Filter) Filter {
+	return func(ctx context.Context, entity *Entity) bool {
+		for _, filter := range filters {
+			if filter(ctx, entity) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not 过滤器不通过时通过
+func Not(filter Filter) Filter {
+	return func(ctx context.Context, entity *Entity) bool {
+		return !filter(ctx, entity)
+	}
+}
+
+// KindFilter 实体类型是kinds之一时通过，一般用于限定其他过滤器生效的实体类型，
+// 例如 Or(Not(KindFilter(EntityFunc)), NameFilter(re)) 只按名称过滤函数
+func KindFilter(kinds ...EntityKind) Filter {
+	return func(ctx context.Context, entity *Entity) bool {
+		for _, kind := range kinds {
+			if entity.Kind == kind {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// NameFilter 实体名称匹配正则表达式时通过
+func NameFilter(re *regexp.Regexp) Filter {
+	return func(ctx context.Context, entity *Entity) bool {
+		return re.MatchString(entity.Name)
+	}
+}
+
+// ReceiverFilter 方法的接收者类型是typeNames之一时通过，接收者类型不区分指针，例如 T 可以匹配 *T，
+// 没有接收者的函数不通过，其他实体直接通过
+func ReceiverFilter(typeNames ...string) Filter {
+	return func(ctx context.Context, entity *Entity) bool {
+		f, ok := entity.Object.(*Func)
+		if !ok {
+			return true
+		}
+		if f.Receiver == nil {
+			return false
+		}
+		for _, name := range typeNames {
+			if name == typeName(f.Receiver.Type) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// AnnotationFilter 类型和函数有匹配前缀的注解时通过，其他实体直接通过，见 WithAnnotationPrefixes
+func AnnotationFilter(prefixes ...string) Filter {
+	return func(ctx context.Context, entity *Entity) bool {
+		switch o := entity.Object.(type) {
+		case *Type:
+			return o.HasAnnotation(prefixes...)
+		case *Func:
+			return o.HasAnnotation(prefixes...)
+		}
+		return true
+	}
+}
+
+// FileFilter 实体所在的文件名匹配任意一个通配符时通过，通配符的语法见 filepath.Match，例如 *_gen.go
+func FileFilter(patterns ...string) Filter {
+	return func(ctx context.Context, entity *Entity) bool {
+		if len(entity.Path) < 2 {
+			return true
+		}
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(pattern, entity.Path[1]); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// filter 使用 WithFilter 的过滤器屏蔽实体，屏蔽后重新生成查找路径
+func (s *Scanner) filter() {
+	if s.options.filter == nil {
+		return
+	}
+	ctx := context.Background()
+	accept := func(kind EntityKind, name string, path Path, object interface{}) bool {
+		return s.options.filter(
+			ctx, &Entity{
+				Kind:   kind,
+				Name:   name,
+				Path:   path,
+				Object: object,
+			},
+		)
+	}
+
+	removed := make(map[*Func]bool)
+	removedFields := make(map[*Field]bool)
+	// 屏蔽的文件和类型中类型的方法可能在其他文件
+	removedTypes := make(map[string]bool)
+	files := s.pkg.Files[:0]
+	for _, file := range s.pkg.Files {
+		if !accept(EntityFile, file.Name, file.Path, file) {
+			for _, f := range file.Funcs {
+				removed[f] = true
+			}
+			for _, t := range file.Types {
+				removedTypes[t.Name] = true
+				for _, field := range t.Fields {
+					removedFields[field] = true
+				}
+			}
+			continue
+		}
+		files = append(files, file)
+
+		values := file.Values[:0]
+		for _, v := range file.Values {
+			if accept(EntityValue, v.Name, v.Path, v) {
+				values = append(values, v)
+			}
+		}
+		file.Values = values
+	}
+	s.pkg.Files = files
+
+	// 先过滤类型，再过滤函数
+	s.pkg.types = make(map[string]*Type)
+	for _, file := range s.pkg.Files {
+		types := file.Types[:0]
+		for _, t := range file.Types {
+			if !accept(EntityType, t.Name, t.Path, t) {
+				removedTypes[t.Name] = true
+				for _, field := range t.Fields {
+					removedFields[field] = true
+				}
+				continue
+			}
+			types = append(types, t)
+			s.pkg.types[t.Name] = t
+
+			fields := t.Fields[:0]
+			for _, field := range t.Fields {
+				if accept(EntityField, field.Name, field.Path, field) {
+					fields = append(fields, field)
+				} else {
+					removedFields[field] = true
+				}
+			}
+			t.Fields = fields
+		}
+		file.Types = types
+	}
+
+	for _, file := range s.pkg.Files {
+		funcs := file.Funcs[:0]
+		for _, f := range file.Funcs {
+			if f.Receiver != nil && removedTypes[typeName(f.Receiver.Type)] {
+				removed[f] = true
+				continue
+			}
+			if accept(EntityFunc, f.Name, f.Path, f) {
+				funcs = append(funcs, f)
+			} else {
+				removed[f] = true
+			}
+		}
+		file.Funcs = funcs
+	}
+
+	for _, file := range s.pkg.Files {
+		for _, t := range file.Types {
+			methods := t.Methods[:0]
+			for _, method := range t.Methods {
+				if removed[method] {
+					continue
+				}
+				// 接口定义的方法不在文件的函数列表中
+				if method.Receiver == nil && !accept(EntityFunc, method.Name, method.Path, method) {
+					continue
+				}
+				methods = append(methods, method)
+			}
+			t.Methods = methods
+		}
+	}
+	// 内嵌类型提升的字段可能来自其他类型，所有类型过滤完成后再处理
+	for _, file := range s.pkg.Files {
+		for _, t := range file.Types {
+			fieldSet := t.FieldSet[:0]
+			for _, field := range t.FieldSet {
+				if !removedFields[field.Field] {
+					fieldSet = append(fieldSet, field)
+				}
+			}
+			t.FieldSet = fieldSet
+		}
+	}
+
+	s.pkg.PathAndTypes = make(map[string]interface{})
+	s.paths()
+}
@@ -0,0 +1,275 @@
+package scan
+
+import (
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pjoc-team/ast/astutil"
+)
+
+// Module 多个包的扫描结果，以及按导入路径和符号名建立的索引
+type Module struct {
+	// Packages 扫描的包，按导入路径排序
+	Packages []*Pkg
+
+	// pkgs 包的ID和导入路径对应的包
+	pkgs map[string]*Pkg
+
+	// symbols key是 导入路径.符号名，例如 github.com/pjoc-team/ast/scan.Pkg，
+	// value是 *Type、*Func、*Value，方法的符号名为 *T.Method 或者 T.Method
+	symbols map[string]interface{}
+}
+
+// ScanPatterns 根据patterns加载并扫描多个包，例如 ./...，加载的选项见 WithLoadOptions。
+// 加载包和类型检查的错误记录在对应包的 Pkg.Errors，字段类型引用的其他包的类型会设置 Field.TypePath
+func ScanPatterns(patterns []string, opts ...Option) (*Module, error) {
+	o := &options{}
+	o.apply(opts...)
+
+	packages, err := astutil.LoadPackages(patterns, o.loadOptions...)
+	if _, ok := err.(astutil.PackageErrors); err != nil && !ok {
+		return nil, err
+	}
+	// 并发数分配给包和包中的文件，总的并发数不超过设置的并发数
+	workers := o.workers()
+	fileWorkers := 1
+	if len(packages) > 0 && workers > len(packages) {
+		fileWorkers = workers / len(packages)
+	}
+	pkgOpts := append(append([]Option(nil), opts...), WithConcurrency(fileWorkers))
+
+	scanned := make([]*Pkg, len(packages))
+	errs := make([]error, len(packages))
+	parallel(
+		workers, len(packages), func(i int) {
+			pkg := packages[i]
+			if len(pkg.Syntax) == 0 {
+				return
+			}
+			// ScanPkg 类型检查的错误已经记录在 Pkg.Errors，只追加加载时的错误
+			loadErrs := pkg.Errors[:len(pkg.Errors):len(pkg.Errors)]
+			p, err := ScanPkg(pkg, pkgOpts...)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			for _, e := range loadErrs {
+				p.Errors = append(p.Errors, e)
+			}
+			scanned[i] = p
+		},
+	)
+	pkgs := make([]*Pkg, 0, len(packages))
+	for i, p := range scanned {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if p != nil {
+			pkgs = append(pkgs, p)
+		}
+	}
+	m := NewModule(pkgs)
+	m.resolveTypePaths()
+	return m, nil
+}
+
+// ScanModule 扫描目录下的所有包，即在dir下扫描 ./...
+func ScanModule(dir string, opts ...Option) (*Module, error) {
+	opts = append([]Option{WithLoadOptions(astutil.WithDir(dir))}, opts...)
+	return ScanPatterns([]string{"./..."}, opts...)
+}
+
+// NewModule 为已扫描的包建立索引，不会修改包
+func NewModule(pkgs []*Pkg) *Module {
+	m := &Module{
+		Packages: append([]*Pkg(nil), pkgs...),
+		pkgs:     make(map[string]*Pkg),
+		symbols:  make(map[string]interface{}),
+	}
+	sort.SliceStable(
+		m.Packages, func(i, j int) bool {
+			if m.Packages[i].PkgPath != m.Packages[j].PkgPath {
+				return m.Packages[i].PkgPath < m.Packages[j].PkgPath
+			}
+			return m.Packages[i].ID < m.Packages[j].ID
+		},
+	)
+	for _, pkg := range m.Packages {
+		m.pkgs[pkg.ID] = pkg
+		if _, ok := m.pkgs[pkg.PkgPath]; !ok {
+			m.pkgs[pkg.PkgPath] = pkg
+		}
+		for _, file := range pkg.Files {
+			for _, t := range file.Types {
+				m.addSymbol(pkg, t.Path, t)
+			}
+			for _, f := range file.Funcs {
+				m.addSymbol(pkg, f.Path, f)
+			}
+			for _, v := range file.Values {
+				m.addSymbol(pkg, v.Path, v)
+			}
+		}
+	}
+	return m
+}
+
+func (m *Module) addSymbol(pkg *Pkg, path Path, symbol interface{}) {
+	if len(path) == 0 {
+		return
+	}
+	key := pkg.PkgPath + "." + path[len(path)-1]
+	if _, ok := m.symbols[key]; !ok {
+		m.symbols[key] = symbol
+	}
+}
+
+// Package 根据导入路径或者包的ID查找包
+func (m *Module) Package(pkgPath string) (*Pkg, bool) {
+	pkg, ok := m.pkgs[pkgPath]
+	return pkg, ok
+}
+
+// Lookup 根据导入路径和符号名查找 *Type、*Func、*Value，方法的符号名为 *T.Method 或者 T.Method
+func (m *Module) Lookup(pkgPath string, name string) (interface{}, bool) {
+	symbol, ok := m.symbols[pkgPath+"."+name]
+	return symbol, ok
+}
+
+// FindPath 根据路径查找对象，路径的第一个元素是包的ID
+func (m *Module) FindPath(path Path) (interface{}, bool) {
+	if len(path) == 0 {
+		return nil, false
+	}
+	pkg, ok := m.pkgs[path[0]]
+	if !ok {
+		return nil, false
+	}
+	return pkg.FindPath(path)
+}
+
+// resolveTypePaths 设置字段、参数和结果的类型的定义的查找路径
+func (m *Module) resolveTypePaths() {
+	for _, pkg := range m.Packages {
+		for _, file := range pkg.Files {
+			resolve := func(fields []*Field) {
+				for _, field := range fields {
+					if t := m.typeRef(pkg, file, field.Type); t != nil {
+						field.TypePath = t.Path
+					}
+				}
+			}
+			for _, t := range file.Types {
+				resolve(t.Fields)
+				for _, method := range t.Methods {
+					// 有接收者的方法在文件的函数列表中处理
+					if method.Receiver == nil {
+						resolve(method.Params)
+						resolve(method.Results)
+					}
+				}
+			}
+			for _, f := range file.Funcs {
+				resolve(f.Params)
+				resolve(f.Results)
+			}
+		}
+	}
+}
+
+// typeRef 查找类型引用的类型，例如 []*pkg.User 的 User，pkg为文件中导入的包名
+func (m *Module) typeRef(pkg *Pkg, file *File, typ string) *Type {
+	name := refTypeName(typ)
+	if name == "" {
+		return nil
+	}
+	pkgPath := pkg.PkgPath
+	if index := strings.Index(name, "."); index >= 0 {
+		pkgPath = importPath(pkg, file, name[:index])
+		name = name[index+1:]
+	}
+	if pkgPath == "" {
+		return nil
+	}
+	symbol, ok := m.Lookup(pkgPath, name)
+	if !ok {
+		return nil
+	}
+	t, _ := symbol.(*Type)
+	return t
+}
+
+// refTypeName 去掉指针、切片、数组、chan和泛型参数后的类型名，例如 []*pkg.List[T] -> pkg.List，
+// map、func、struct、interface等没有类型名的类型返回空
+func refTypeName(typ string) string {
+	for {
+		switch {
+		case strings.HasPrefix(typ, "*"):
+			typ = typ[1:]
+		case strings.HasPrefix(typ, "..."):
+			typ = typ[3:]
+		case strings.HasPrefix(typ, "<-chan "):
+			typ = typ[len("<-chan "):]
+		case strings.HasPrefix(typ, "chan<- "):
+			typ = typ[len("chan<- "):]
+		case strings.HasPrefix(typ, "chan "):
+			typ = typ[len("chan "):]
+		case strings.HasPrefix(typ, "(") && strings.HasSuffix(typ, ")"):
+			// chan (<-chan T)
+			typ = typ[1 : len(typ)-1]
+		case strings.HasPrefix(typ, "["):
+			index := strings.Index(typ, "]")
+			if index < 0 {
+				return ""
+			}
+			typ = typ[index+1:]
+		case strings.HasPrefix(typ, "map["), strings.HasPrefix(typ, "func("),
+			strings.HasPrefix(typ, "struct{"), strings.HasPrefix(typ, "struct {"),
+			strings.HasPrefix(typ, "interface{"), strings.HasPrefix(typ, "interface {"):
+			return ""
+		default:
+			return typeName(typ)
+		}
+	}
+}
+
+// importPath 根据文件中导入的包名查找导入路径，根据类型信息生成的类型使用包名，不一定在当前文件导入。
+// 使用 WithOnlyExported 时 File.Imports 为空，所以从语法树和类型信息查找
+func importPath(pkg *Pkg, file *File, name string) string {
+	info := pkg.p.TypesInfo
+	if info == nil {
+		return ""
+	}
+	var found string
+	for i, f := range pkg.p.Syntax {
+		for _, is := range f.Imports {
+			var obj types.Object
+			if is.Name != nil {
+				obj = info.Defs[is.Name]
+			} else {
+				obj = info.Implicits[is]
+			}
+			pkgName, ok := obj.(*types.PkgName)
+			if !ok || pkgName.Name() != name {
+				continue
+			}
+			if i < len(pkg.p.GoFiles) && filepath.Base(pkg.p.GoFiles[i]) == file.Name {
+				return pkgName.Imported().Path()
+			}
+			if found == "" {
+				found = pkgName.Imported().Path()
+			}
+		}
+	}
+	if found != "" || pkg.p.Types == nil {
+		return found
+	}
+	for _, imported := range pkg.p.Types.Imports() {
+		if imported.Name() == name {
+			return imported.Path()
+		}
+	}
+	return ""
+}
@@ -1,9 +1,19 @@
 package scan
 
+import (
+	"runtime"
+
+	"github.com/pjoc-team/ast/astutil"
+)
+
 // options 扫描选项
 type options struct {
-	onlyExported bool
-	filter       Filter
+	onlyExported       bool
+	filter             Filter
+	annotationPrefixes []string
+	unexportedValues   bool
+	loadOptions        []astutil.LoadOption
+	concurrency        int
 }
 
 func (o *options) apply(opts ...Option) {
@@ -12,19 +22,58 @@ func (o *options) apply(opts ...Option) {
 	}
 }
 
+// workers 并发数，没有设置时为 runtime.GOMAXPROCS(0)
+func (o *options) workers() int {
+	if o.concurrency < 1 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.concurrency
+}
+
 // Option 选项
 type Option func(o *options)
 
 // WithOnlyExported 只导出大写开头的函数
 func WithOnlyExported(onlyExported bool) Option {
 	return func(o *options) {
-		o.onlyExported = true
+		o.onlyExported = onlyExported
 	}
 }
 
-// WithFilter 过滤器
+// WithUnexportedValues 是否扫描小写开头的变量和常量，默认只扫描大写开头的，
+// 使用了 WithOnlyExported 时不生效
+func WithUnexportedValues(unexportedValues bool) Option {
+	return func(o *options) {
+		o.unexportedValues = unexportedValues
+	}
+}
+
+// WithFilter 过滤器，扫描完成后过滤文件、类型、函数、字段和变量，见 And、Or、Not 等内置的过滤器
 func WithFilter(filter Filter) Option {
 	return func(o *options) {
 		o.filter = filter
 	}
 }
+
+// WithAnnotationPrefixes 识别文档中以这些前缀开头的注解，例如 @compose、+action，
+// 匹配的行会保存在 Func 和 Type 的 Annotations
+func WithAnnotationPrefixes(prefixes ...string) Option {
+	return func(o *options) {
+		o.annotationPrefixes = append(o.annotationPrefixes, prefixes...)
+	}
+}
+
+// WithLoadOptions ScanPatterns、ScanModule 加载包的选项，例如 astutil.WithDir、astutil.WithTags
+func WithLoadOptions(opts ...astutil.LoadOption) Option {
+	return func(o *options) {
+		o.loadOptions = append(o.loadOptions, opts...)
+	}
+}
+
+// WithConcurrency 扫描文件和包的最大并发数，小于1时为 runtime.GOMAXPROCS(0)。
+// 扫描结果的顺序和并发数无关，WithFilter 的过滤器可能会被并发调用
+func WithConcurrency(concurrency int) Option {
+	return func(o *options) {
+		o.concurrency = concurrency
+	}
+}
@@ -0,0 +1,33 @@
+package scan
+
+import "sync"
+
+// parallel 使用最多n个goroutine执行 fn(0) ... fn(count-1)，全部执行完成后返回。
+// 结果由fn按下标保存，所以输出的顺序和并发数无关
+func parallel(n int, count int, fn func(i int)) {
+	if n > count {
+		n = count
+	}
+	if n <= 1 {
+		for i := 0; i < count; i++ {
+			fn(i)
+		}
+		return
+	}
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -104,6 +104,7 @@ func (s *Scanner) filePath(p Path, file *File) {
 		t.Path = ffp
 		s.addPath(ffp, t)
 		s.fieldPath(t, ffp)
+		s.methodPath(t, ffp)
 	}
 }
 
@@ -117,6 +118,20 @@ func (s *Scanner) fieldPath(t *Type, ffp Path) {
 	}
 }
 
+// methodPath 方法查找路径
+func (s *Scanner) methodPath(t *Type, ffp Path) {
+	for _, method := range t.Methods {
+		// 有接收者的方法已经以 Pkg -> File -> Type.Func 的路径注册
+		if method.Receiver != nil {
+			continue
+		}
+		mp := ffp.Clone()
+		mp = append(mp, method.Name)
+		method.Path = mp
+		s.addPath(mp, method)
+	}
+}
+
 // fieldPath 字段查找路径
 func (s *Scanner) valuePath(t *Type, ffp Path) {
 	for _, field := range t.Fields {
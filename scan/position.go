@@ -0,0 +1,40 @@
+package scan
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// Position 源码中的位置，由包的 FileSet 计算
+type Position struct {
+	// Filename 文件的完整路径
+	Filename string `json:"filename" yaml:"filename"`
+
+	// Offset 从0开始的字节偏移量
+	Offset int `json:"offset" yaml:"offset"`
+
+	// Line 从1开始的行号
+	Line int `json:"line" yaml:"line"`
+
+	// Column 从1开始的列号，按字节计算
+	Column int `json:"column" yaml:"column"`
+}
+
+// String 打印为 file:line:column 的格式，和编译器报错的格式一致
+func (p *Position) String() string {
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// position 计算源码位置，没有 FileSet 或者位置无效时返回nil
+func (s *Scanner) position(pos token.Pos) *Position {
+	if !pos.IsValid() || s.pkg.p.Fset == nil {
+		return nil
+	}
+	p := s.pkg.p.Fset.Position(pos)
+	return &Position{
+		Filename: p.Filename,
+		Offset:   p.Offset,
+		Line:     p.Line,
+		Column:   p.Column,
+	}
+}
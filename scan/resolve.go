@@ -0,0 +1,343 @@
+package scan
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"log"
+	"strings"
+
+	"github.com/pjoc-team/ast/astutil"
+)
+
+// resolve 所有文件扫描完成后，处理跨文件的关联关系，例如内嵌接口、接收者的方法
+func (s *Scanner) resolve() {
+	for _, file := range s.pkg.Files {
+		for _, t := range file.Types {
+			s.pkg.types[t.Name] = t
+		}
+	}
+	s.resolveMethods()
+	resolved := make(map[*Type]bool)
+	for _, file := range s.pkg.Files {
+		for _, t := range file.Types {
+			s.resolveInterface(t, resolved)
+			s.resolveFieldSet(t)
+		}
+	}
+	s.resolveEnums()
+}
+
+// resolveMethods 把方法挂到接收者的类型上，接收者的类型可能定义在其他文件
+func (s *Scanner) resolveMethods() {
+	for _, file := range s.pkg.Files {
+		for _, f := range file.Funcs {
+			if f.Receiver == nil {
+				continue
+			}
+			t, ok := s.pkg.types[typeName(f.Receiver.Type)]
+			if !ok || t.Type == TypeInterface {
+				continue
+			}
+			t.Methods = append(t.Methods, f)
+		}
+	}
+}
+
+// resolveEnums 根据类型信息，把类型为当前包的类型的常量作为该类型的枚举成员
+func (s *Scanner) resolveEnums() {
+	info := s.pkg.p.TypesInfo
+	if info == nil {
+		return
+	}
+	for _, file := range s.pkg.p.Syntax {
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				// const 声明的 Spec 一定是 ValueSpec
+				vs := spec.(*ast.ValueSpec)
+				doc := vs.Doc
+				if doc == nil {
+					doc = s.docs[vs]
+				}
+				for _, name := range vs.Names {
+					// _ 一般用于跳过零值，不能作为值使用
+					if name.Name == "_" {
+						continue
+					}
+					c, ok := info.Defs[name].(*types.Const)
+					if !ok {
+						continue
+					}
+					named, ok := c.Type().(*types.Named)
+					if !ok || named.Obj().Pkg() != c.Pkg() {
+						continue
+					}
+					t, ok := s.pkg.types[named.Obj().Name()]
+					if !ok {
+						continue
+					}
+					t.Enum = append(
+						t.Enum, &EnumMember{
+							Name:  name.Name,
+							Value: c.Val().String(),
+							Doc:   astutil.ParseComment(doc),
+							exact: c.Val().ExactString(),
+						},
+					)
+				}
+			}
+		}
+	}
+}
+
+// fieldSetEntry 查找字段时某一深度的结构体
+type fieldSetEntry struct {
+	fields []*Field
+	// via 访问该结构体经过的内嵌字段名
+	via []string
+	// key 结构体的类型，用于判断类型是否已经查找过
+	key interface{}
+}
+
+// resolveFieldSet 按go的规则逐层查找内嵌类型提升的字段：浅的字段屏蔽深的同名字段，
+// 同一深度有多个同名字段时有歧义
+func (s *Scanner) resolveFieldSet(t *Type) {
+	if t.Type != TypeStruct {
+		return
+	}
+	// resolved 已经确定的字段名，包括有歧义的字段名
+	resolved := make(map[string]bool)
+	seen := map[interface{}]bool{t: true}
+	current := []*fieldSetEntry{{fields: t.Fields}}
+	for len(current) > 0 {
+		var next []*fieldSetEntry
+		names := make([]string, 0)
+		candidates := make(map[string][]*SelectableField)
+		for _, entry := range current {
+			for _, field := range entry.fields {
+				if !resolved[field.Name] && s.isSelectable(field) {
+					if _, ok := candidates[field.Name]; !ok {
+						names = append(names, field.Name)
+					}
+					candidates[field.Name] = append(
+						candidates[field.Name], &SelectableField{
+							Field: field,
+							Via:   entry.via,
+						},
+					)
+				}
+				if !field.Embedded {
+					continue
+				}
+				fields, key := s.embeddedFields(field)
+				if key == nil || seen[key] {
+					continue
+				}
+				via := make([]string, 0, len(entry.via)+1)
+				via = append(via, entry.via...)
+				via = append(via, field.Name)
+				next = append(
+					next, &fieldSetEntry{
+						fields: fields,
+						via:    via,
+						key:    key,
+					},
+				)
+			}
+		}
+		for _, name := range names {
+			resolved[name] = true
+			if fields := candidates[name]; len(fields) == 1 {
+				t.FieldSet = append(t.FieldSet, fields[0])
+			} else {
+				t.AmbiguousFields = append(t.AmbiguousFields, name)
+			}
+		}
+		// 同一深度内嵌了多次的类型，字段会有歧义，所以同一深度内不去重
+		for _, entry := range next {
+			seen[entry.key] = true
+		}
+		current = next
+	}
+}
+
+// embeddedFields 内嵌类型的字段，同个包的类型直接使用扫描结果，其他包的类型使用类型信息，
+// 返回的key用于判断类型是否已经查找过，不是结构体时返回nil
+func (s *Scanner) embeddedFields(field *Field) ([]*Field, interface{}) {
+	if expr, ok := s.embedFields[field]; ok {
+		if embed, ok := s.pkg.types[typeName(field.Type)]; ok && embed.Type == TypeStruct {
+			return embed.Fields, embed
+		}
+		info := s.pkg.p.TypesInfo
+		if info == nil {
+			return nil, nil
+		}
+		return s.structFields(info.TypeOf(expr))
+	}
+	if v, ok := s.fieldVars[field]; ok {
+		return s.structFields(v.Type())
+	}
+	return nil, nil
+}
+
+// structFields 根据类型信息生成结构体的字段
+func (s *Scanner) structFields(tp types.Type) ([]*Field, interface{}) {
+	if tp == nil {
+		return nil, nil
+	}
+	if ptr, ok := tp.(*types.Pointer); ok {
+		tp = ptr.Elem()
+	}
+	st, ok := tp.Underlying().(*types.Struct)
+	if !ok {
+		return nil, nil
+	}
+	fields := make([]*Field, 0, st.NumFields())
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		f := &Field{
+			Pos:      s.position(v.Pos()),
+			Name:     v.Name(),
+			Type:     types.TypeString(v.Type(), s.qualifier),
+			Embedded: v.Embedded(),
+			Tag:      st.Tag(i),
+		}
+		if f.Tag != "" {
+			f.Tags, _ = ParseTag(f.Tag)
+		}
+		s.fieldVars[f] = v
+		fields = append(fields, f)
+	}
+	return fields, tp
+}
+
+// isSelectable 字段是否可以访问，其他包的小写开头的字段不能访问
+func (s *Scanner) isSelectable(field *Field) bool {
+	v, ok := s.fieldVars[field]
+	if !ok || v.Exported() {
+		return true
+	}
+	return v.Pkg() == s.pkg.p.Types && !s.options.onlyExported
+}
+
+// resolveInterface 合并内嵌接口的方法
+func (s *Scanner) resolveInterface(t *Type, resolved map[*Type]bool) {
+	if t.Type != TypeInterface || resolved[t] {
+		return
+	}
+	// 先标记，避免非法的循环内嵌导致死循环
+	resolved[t] = true
+	for _, expr := range s.embeds[t] {
+		methods, err := s.embeddedMethods(expr, resolved)
+		if err != nil {
+			log.Printf("failed to resolve embedded type of: %v error: %v", t.Name, err.Error())
+			s.pkg.Errors = append(s.pkg.Errors, err)
+			continue
+		}
+		for _, method := range methods {
+			if findMethod(t.Methods, method.Name) != nil {
+				continue
+			}
+			m := *method
+			m.Path = nil
+			t.Methods = append(t.Methods, &m)
+		}
+	}
+}
+
+// embeddedMethods 查找内嵌接口的方法，同个包的接口直接使用扫描结果，其他包的接口使用类型信息
+func (s *Scanner) embeddedMethods(expr ast.Expr, resolved map[*Type]bool) ([]*Func, error) {
+	if ident, ok := expr.(*ast.Ident); ok {
+		if embed, ok := s.pkg.types[ident.Name]; ok && embed.Type == TypeInterface {
+			s.resolveInterface(embed, resolved)
+			return embed.Methods, nil
+		}
+	}
+
+	info := s.pkg.p.TypesInfo
+	if info == nil {
+		return nil, fmt.Errorf("no type info to resolve embedded type: %v", expr)
+	}
+	tp := info.TypeOf(expr)
+	if tp == nil {
+		return nil, fmt.Errorf("not found type of embedded type: %v", expr)
+	}
+	iface, ok := tp.Underlying().(*types.Interface)
+	if !ok {
+		// 类型约束中的类型元素，例如 ~int，不提供方法
+		return nil, nil
+	}
+	methods := make([]*Func, 0, iface.NumMethods())
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		if s.options.onlyExported && !method.Exported() {
+			continue
+		}
+		methods = append(methods, s.funcOf(method))
+	}
+	return methods, nil
+}
+
+// funcOf 根据类型信息生成函数
+func (s *Scanner) funcOf(fn *types.Func) *Func {
+	signature := fn.Type().(*types.Signature)
+	f := &Func{
+		Name:    fn.Name(),
+		Pos:     s.position(fn.Pos()),
+		Params:  s.fieldsOf(signature.Params(), signature.Variadic()),
+		Results: s.fieldsOf(signature.Results(), false),
+	}
+	return f
+}
+
+func (s *Scanner) fieldsOf(tuple *types.Tuple, variadic bool) []*Field {
+	fields := make([]*Field, 0, tuple.Len())
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		var typ string
+		if slice, ok := v.Type().(*types.Slice); ok && variadic && i == tuple.Len()-1 {
+			typ = "..." + types.TypeString(slice.Elem(), s.qualifier)
+		} else {
+			typ = types.TypeString(v.Type(), s.qualifier)
+		}
+		fields = append(
+			fields, &Field{
+				Name: v.Name(),
+				Type: typ,
+			},
+		)
+	}
+	return fields
+}
+
+// qualifier 当前包的类型不需要包名，其他包的类型使用包名
+func (s *Scanner) qualifier(pkg *types.Package) string {
+	if pkg == nil || pkg.Path() == s.pkg.p.PkgPath {
+		return ""
+	}
+	return pkg.Name()
+}
+
+// findMethod 根据名称查找方法
+func findMethod(methods []*Func, name string) *Func {
+	for _, method := range methods {
+		if method.Name == name {
+			return method
+		}
+	}
+	return nil
+}
+
+// typeName 去掉指针和泛型参数后的类型名，例如 *List[T] -> List
+func typeName(typ string) string {
+	typ = strings.TrimPrefix(typ, "*")
+	if index := strings.Index(typ, "["); index >= 0 {
+		typ = typ[:index]
+	}
+	return typ
+}
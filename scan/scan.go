@@ -2,9 +2,13 @@ package scan
 
 import (
 	"errors"
+	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
 	"log"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/pjoc-team/ast/astutil"
@@ -33,12 +37,74 @@ const (
 
 	// TypeChan chan类型
 	TypeChan TypeT = "chan"
+
+	// TypePointer 指针类型
+	TypePointer TypeT = "pointer"
 )
 
+// ChanDir chan的方向
+type ChanDir string
+
+const (
+	// ChanBoth 双向chan，例如 chan T
+	ChanBoth ChanDir = "both"
+
+	// ChanSend 只写chan，例如 chan<- T
+	ChanSend ChanDir = "send"
+
+	// ChanRecv 只读chan，例如 <-chan T
+	ChanRecv ChanDir = "recv"
+)
+
+// ValueKind 变量的声明方式
+type ValueKind string
+
+const (
+	// ValueConst 常量，例如 const A = 1
+	ValueConst ValueKind = "const"
+
+	// ValueVar 变量，例如 var A = 1
+	ValueVar ValueKind = "var"
+)
+
+// ParseChanType 解析chan类型的字符串，返回元素类型和方向，如果不是chan类型则ok为false
+func ParseChanType(typ string) (elem string, dir ChanDir, ok bool) {
+	switch {
+	case strings.HasPrefix(typ, "<-chan "):
+		elem, dir = typ[len("<-chan "):], ChanRecv
+	case strings.HasPrefix(typ, "chan<- "):
+		elem, dir = typ[len("chan<- "):], ChanSend
+	case strings.HasPrefix(typ, "chan "):
+		elem, dir = typ[len("chan "):], ChanBoth
+	default:
+		return "", "", false
+	}
+	elem = strings.TrimSpace(elem)
+	if strings.HasPrefix(elem, "(") && strings.HasSuffix(elem, ")") {
+		elem = elem[1 : len(elem)-1]
+	}
+	return elem, dir, true
+}
+
 // Scanner 扫描器
 type Scanner struct {
 	pkg     *Pkg
 	options *options
+
+	// embeds 接口内嵌类型的表达式，用于合并内嵌接口的方法
+	embeds map[*Type][]ast.Expr
+
+	// docs 没有括号的声明，例如 type T struct{}，文档在 GenDecl 上而不在 Spec 上
+	docs map[ast.Spec]*ast.CommentGroup
+
+	// embedFields 结构体内嵌字段的类型表达式，用于查找内嵌类型提升的字段
+	embedFields map[*Field]ast.Expr
+
+	// fieldVars 根据类型信息生成的字段，用于查找其他包的内嵌类型提升的字段
+	fieldVars map[*Field]*types.Var
+
+	// valueNames 声明的所有变量名，ast.FileExports 会删除未导出的变量名，导致变量名和值对不上
+	valueNames map[*ast.ValueSpec][]*ast.Ident
 }
 
 // Pkg 包解析器
@@ -49,6 +115,9 @@ type Pkg struct {
 	// 导入时可用的ID
 	ID string
 
+	// PkgPath 导入路径，例如 github.com/pjoc-team/ast/scan
+	PkgPath string
+
 	// 文档
 	Doc string
 
@@ -62,6 +131,9 @@ type Pkg struct {
 	// -> scan.go -> File 对应Type: File
 	PathAndTypes map[string]interface{} `json:"-"`
 
+	// types 当前包定义的类型，key是类型名
+	types map[string]*Type
+
 	p *packages.Package
 }
 
@@ -70,6 +142,12 @@ type File struct {
 	// Path 查找该函数的路径，一般是从 Pkg -> File -> Func/Struct
 	Path Path
 
+	// Pos 开始位置
+	Pos *Position
+
+	// End 结束位置
+	End *Position
+
 	// Name 文件名
 	Name string
 
@@ -94,6 +172,12 @@ type Import struct {
 	// Path 查找该导入的路径，一般是从 Pkg -> File -> Import
 	Path Path
 
+	// Pos 开始位置
+	Pos *Position
+
+	// End 结束位置
+	End *Position
+
 	// Name 命名，可能为空
 	Name string
 
@@ -117,15 +201,32 @@ func (i *Import) AliasName() string {
 type Value struct {
 	// Path 查找该字段的路径，一般是从 Pkg -> File -> Value
 	Path Path `json:"path" yaml:"path"`
+	// Pos 开始位置，即变量名的位置
+	Pos *Position `json:"pos" yaml:"pos"`
+	// End 结束位置，即值的结束位置，没有值时为类型或者变量名的结束位置
+	End *Position `json:"end" yaml:"end"`
 
 	// Name 变量名
 	Name string
-	// Type 变量类型
+	// Kind 声明方式，常量或变量
+	Kind ValueKind
+	// Type 变量类型，没有声明类型时为值的类型
 	Type string
+	// DeclaredType 声明的类型，例如 var A int64 = 1 的 int64，没有声明类型时为空
+	DeclaredType string
 	// Doc 文档
 	Doc string
+	// ParsedDoc 结构化的文档，没有文档时为nil
+	ParsedDoc *astutil.Doc
 	// Value 变量值
 	Value string
+	// ResolvedType 根据类型信息得到的类型，例如 Kind、untyped int，没有类型信息时为空
+	ResolvedType string
+	// ValueExpr 值的表达式的树形结构，没有值时为nil
+	ValueExpr *astutil.ExprNode
+	// ExactValue 常量的精确值，例如 iota 计算后的 1、常量表达式 1 << 10 计算后的 1024，
+	// 字符串带引号，浮点数为分数，变量为空
+	ExactValue string
 }
 
 // Type 类型定义，可能是Array/Struct/Operation/Interface/Map/Chan等
@@ -133,6 +234,12 @@ type Type struct {
 	// Path 查找该类型定义的路径，一般是从 Pkg -> File -> Func/Struct
 	Path Path
 
+	// Pos 开始位置，即类型名的位置
+	Pos *Position
+
+	// End 结束位置
+	End *Position
+
 	// Type 基础类型，例如Array/Struct/Operation/Interface/Map/Chan
 	Type TypeT
 
@@ -142,8 +249,80 @@ type Type struct {
 	// Fields 如果是struct类型，则会有多个Fields
 	Fields []*Field
 
+	// FieldSet 如果是struct类型，则为可以通过 x.f 访问的字段，包括内嵌类型提升的字段。
+	// 按go的规则，同名的字段只保留深度最浅的，同一深度有多个同名字段时有歧义，记录在 AmbiguousFields
+	FieldSet []*SelectableField
+
+	// AmbiguousFields 有歧义不能访问的字段名
+	AmbiguousFields []string
+
+	// Underlying 类型定义的原始代码，例如 map[string]int
+	Underlying string
+
+	// Alias 是否是类型别名，例如 type A = B
+	Alias bool
+
+	// Elem 元素类型，如果是Array/Map/Chan/Pointer类型，则为对应的元素类型
+	Elem string
+
+	// Key 如果是map类型，则为key的类型
+	Key string
+
+	// Len 如果是定长数组，则为数组长度，例如 [4]int 的长度为 4
+	Len string
+
+	// ChanDir 如果是chan类型，则为chan的方向
+	ChanDir ChanDir
+
+	// Signature 如果是func类型，则为函数签名
+	Signature *Func
+
+	// Methods 方法列表，如果是interface类型，则为接口定义的方法（包括内嵌接口的方法），
+	// 否则为该类型声明的方法（包括指针接收者的方法），可能分布在同个package的不同file
+	Methods []*Func
+
+	// Embeds 内嵌的类型，例如 interface { io.Reader } 的 io.Reader，
+	// 类型约束中的类型元素也会记录在这里，例如 ~int | ~string
+	Embeds []string
+
+	// TypeParams 泛型类型的类型参数，Field的Type为类型约束，例如 type List[T any] 的 T any
+	TypeParams []*Field
+
+	// Enum 枚举成员，即当前包中类型为该类型的常量，按声明的顺序排列，没有时为nil
+	Enum []*EnumMember
+
 	// Doc 文档说明
 	Doc string
+
+	// ParsedDoc 结构化的文档，没有文档时为nil
+	ParsedDoc *astutil.Doc
+
+	// Annotations 文档中匹配注解前缀的行，见 WithAnnotationPrefixes
+	Annotations []string
+}
+
+// SelectableField 可以通过 x.f 访问的字段
+type SelectableField struct {
+	// Field 字段，其他包的内嵌类型提升的字段没有Path
+	Field *Field `json:"field" yaml:"field"`
+
+	// Via 访问该字段经过的内嵌字段名，例如 Req 内嵌 Base 时，Base 的字段 ID 为 [Base]，自身的字段为空
+	Via []string `json:"via" yaml:"via"`
+}
+
+// EnumMember 枚举成员，例如 const ( StatusPaid Status = iota ) 的 StatusPaid
+type EnumMember struct {
+	// Name 常量名
+	Name string `json:"name" yaml:"name"`
+
+	// Value 常量值，例如 iota 计算后的 1，字符串带引号
+	Value string `json:"value" yaml:"value"`
+
+	// Doc 文档
+	Doc string `json:"doc" yaml:"doc"`
+
+	// exact 完整的常量值，Value 会截断过长的字符串，判断值是否相同时使用
+	exact string
 }
 
 // Func 函数
@@ -151,10 +330,19 @@ type Func struct {
 	// Path 查找该函数的路径，一般是从 Pkg -> File -> Func/Struct
 	Path Path `json:"path" yaml:"path"`
 
+	// Pos 开始位置，接口定义的方法为方法名的位置
+	Pos *Position `json:"pos" yaml:"pos"`
+
+	// End 结束位置，根据类型信息得到的方法没有结束位置
+	End *Position `json:"end" yaml:"end"`
+
 	// Receiver 接收者，如果函数是属于某个类型的，则会有接收者。
 	// 接收者应该是在同个package，但有可能在不同的file
 	Receiver *Field `json:"receiver" yaml:"receiver"`
 
+	// PointerReceiver 接收者是否是指针，例如 func (t *T) Foo()
+	PointerReceiver bool `json:"pointer_receiver" yaml:"pointerReceiver"`
+
 	// Name 函数名
 	Name string `json:"name" yaml:"name"`
 
@@ -164,8 +352,17 @@ type Func struct {
 	// Results 响应列表
 	Results []*Field `json:"results" yaml:"results"`
 
+	// TypeParams 泛型函数的类型参数，Field的Type为类型约束，例如 func Map[K comparable, V any] 的 K comparable
+	TypeParams []*Field `json:"type_params" yaml:"typeParams"`
+
 	// Doc 文档
 	Doc string `json:"doc" yaml:"doc"`
+
+	// ParsedDoc 结构化的文档，没有文档时为nil
+	ParsedDoc *astutil.Doc `json:"parsed_doc" yaml:"parsedDoc"`
+
+	// Annotations 文档中匹配注解前缀的行，见 WithAnnotationPrefixes
+	Annotations []string `json:"annotations" yaml:"annotations"`
 }
 
 // Field 字段
@@ -173,14 +370,36 @@ type Field struct {
 	// Path 查找该字段的路径，一般是从 Pkg -> File -> Func/Struct -> Field
 	Path Path `json:"path" yaml:"path"`
 
+	// Pos 开始位置，多个字段共用一个类型时为字段名的位置，例如 A, B int
+	Pos *Position `json:"pos" yaml:"pos"`
+
+	// End 结束位置
+	End *Position `json:"end" yaml:"end"`
+
 	// Name 字段名
 	Name string `json:"name" yaml:"name"`
 
 	// Type 字段类型
 	Type string `json:"type" yaml:"type"`
 
+	// TypePath 字段类型的定义的查找路径，例如 []*pkg.User 的 User，可能在其他包。
+	// 只有通过 ScanPatterns 扫描时才会设置，类型不是扫描过的包定义的时为nil
+	TypePath Path `json:"type_path" yaml:"typePath"`
+
+	// Embedded 是否是结构体的内嵌字段，例如 struct { *pkg.Base } 的 Base，字段名为去掉指针和包名的类型名
+	Embedded bool `json:"embedded" yaml:"embedded"`
+
+	// Tag 结构体字段的原始标签，不包括反引号，例如 json:"name,omitempty"
+	Tag string `json:"tag" yaml:"tag"`
+
+	// Tags 解析后的标签，key是标签名，例如 json，没有标签时为nil
+	Tags map[string]*Tag `json:"tags" yaml:"tags"`
+
 	// Doc 文档
 	Doc string `json:"doc" yaml:"doc"`
+
+	// ParsedDoc 结构化的文档，没有文档时为nil
+	ParsedDoc *astutil.Doc `json:"parsed_doc" yaml:"parsedDoc"`
 }
 
 // ScanPkg 扫描包
@@ -188,26 +407,105 @@ func ScanPkg(pkg *packages.Package, opts ...Option) (*Pkg, error) {
 	p := &Pkg{
 		Name:         pkg.Name,
 		ID:           pkg.ID,
+		PkgPath:      pkg.PkgPath,
 		PathAndTypes: make(map[string]interface{}),
+		types:        make(map[string]*Type),
 		p:            pkg,
 	}
 
 	o := &options{}
 	o.apply(opts...)
 
-	s := &Scanner{
+	// 内嵌接口等需要依赖类型信息
+	if err := astutil.CheckTypes(pkg); err != nil {
+		log.Printf("failed to check types of package: %v error: %v", pkg.ID, err.Error())
+		p.Errors = append(p.Errors, err)
+	}
+
+	s := newScanner(p, o)
+	// 每个文件使用独立的Scanner并发扫描，再按文件的顺序合并，保证结果的顺序和并发数无关
+	scanners := make([]*Scanner, len(pkg.Syntax))
+	codeFiles := make([]*File, len(pkg.Syntax))
+	errs := make([][]error, len(pkg.Syntax))
+	parallel(
+		o.workers(), len(pkg.Syntax), func(i int) {
+			fs := newScanner(
+				&Pkg{
+					Name:    p.Name,
+					ID:      p.ID,
+					PkgPath: p.PkgPath,
+					p:       pkg,
+				}, o,
+			)
+			codeFiles[i], errs[i] = fs.processFile(pkg.GoFiles[i], pkg.Syntax[i])
+			scanners[i] = fs
+		},
+	)
+	for i := range pkg.Syntax {
+		s.merge(scanners[i])
+		p.Errors = append(p.Errors, errs[i]...)
+		if codeFiles[i] != nil {
+			p.Files = append(p.Files, codeFiles[i])
+		}
+	}
+	s.resolve()
+	s.paths()
+	s.filter()
+	return p, nil
+}
+
+func newScanner(p *Pkg, o *options) *Scanner {
+	return &Scanner{
 		pkg:     p,
 		options: o,
+		embeds:  make(map[*Type][]ast.Expr),
+		docs:    make(map[ast.Spec]*ast.CommentGroup),
+
+		embedFields: make(map[*Field]ast.Expr),
+		fieldVars:   make(map[*Field]*types.Var),
+		valueNames:  make(map[*ast.ValueSpec][]*ast.Ident),
+	}
+}
+
+// merge 合并扫描单个文件的Scanner的结果
+func (s *Scanner) merge(fs *Scanner) {
+	if fs.pkg.Doc != "" {
+		s.pkg.Doc = fs.pkg.Doc
+	}
+	s.pkg.Errors = append(s.pkg.Errors, fs.pkg.Errors...)
+	for t, embeds := range fs.embeds {
+		s.embeds[t] = embeds
 	}
-	for i, file := range pkg.Syntax {
-		goFile := pkg.GoFiles[i]
-		codeFile, errs := s.processFile(goFile, file)
-		p.Errors = append(p.Errors, errs...)
-		if codeFile != nil {
-			p.Files = append(p.Files, codeFile)
+	for spec, doc := range fs.docs {
+		s.docs[spec] = doc
+	}
+	for field, expr := range fs.embedFields {
+		s.embedFields[field] = expr
+	}
+	for spec, names := range fs.valueNames {
+		s.valueNames[spec] = names
+	}
+}
+
+// ScanSource 扫描内存中的源码，不需要文件存在于磁盘，key是文件名，value是文件内容。
+// 语法错误和类型错误会记录在 Pkg.Errors，只有没有可扫描的文件时才返回错误
+func ScanSource(pkgPath string, files map[string]string, opts ...Option) (*Pkg, error) {
+	pkg, err := astutil.LoadSource(pkgPath, files)
+	if len(pkg.Syntax) == 0 {
+		if err == nil {
+			err = fmt.Errorf("no go files of package: %v", pkgPath)
+		}
+		return nil, err
+	}
+	p, scanErr := ScanPkg(pkg, opts...)
+	if scanErr != nil {
+		return nil, scanErr
+	}
+	if errs, ok := err.(astutil.PackageErrors); ok {
+		for _, e := range errs {
+			p.Errors = append(p.Errors, e)
 		}
 	}
-	s.paths()
 	return p, nil
 }
 
@@ -221,14 +519,97 @@ func (p *Pkg) FindPath(packagePath Path) (interface{}, bool) {
 	return object, ok
 }
 
+// HasAnnotation 是否有以任意一个前缀开头的注解
+func (t *Type) HasAnnotation(prefixes ...string) bool {
+	return hasAnnotation(t.Annotations, prefixes)
+}
+
+// HasAnnotation 是否有以任意一个前缀开头的注解
+func (f *Func) HasAnnotation(prefixes ...string) bool {
+	return hasAnnotation(f.Annotations, prefixes)
+}
+
+func hasAnnotation(annotations []string, prefixes []string) bool {
+	for _, annotation := range annotations {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(annotation, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MethodsOf 查找类型的方法集，遵循go的规则：
+// T 的方法集只包含值接收者的方法，*T 的方法集包含值接收者和指针接收者的方法，
+// interface 的方法集为接口定义的方法
+func (p *Pkg) MethodsOf(typeName string) []*Func {
+	pointer := strings.HasPrefix(typeName, "*")
+	t := p.findType(strings.TrimPrefix(typeName, "*"))
+	if t == nil {
+		return nil
+	}
+	return t.MethodSet(pointer)
+}
+
+func (p *Pkg) findType(name string) *Type {
+	if t, ok := p.types[name]; ok {
+		return t
+	}
+	for _, file := range p.Files {
+		for _, t := range file.Types {
+			if t.Name == name {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// MethodSet 类型的方法集，pointer为true时返回 *T 的方法集
+func (t *Type) MethodSet(pointer bool) []*Func {
+	if pointer || t.Type == TypeInterface {
+		return t.Methods
+	}
+	methods := make([]*Func, 0, len(t.Methods))
+	for _, method := range t.Methods {
+		if !method.PointerReceiver {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
 func (s *Scanner) processFile(goFile string, file *ast.File) (codeFile *File, errs []error) {
 	codeFile = &File{
 		Source: path.SourcePath(goFile),
 		Name:   filepath.Base(goFile),
+		Pos:    s.position(file.Pos()),
+		End:    s.position(file.End()),
+	}
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			if vs, ok := spec.(*ast.ValueSpec); ok {
+				s.valueNames[vs] = append([]*ast.Ident(nil), vs.Names...)
+			}
+		}
 	}
 	if !s.isExported(file) {
 		return nil, nil
 	}
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Lparen.IsValid() || gd.Doc == nil {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			s.docs[spec] = gd.Doc
+		}
+	}
 	wf := s.walk(s.pkg, codeFile, errs)
 	ast.Inspect(file, wf)
 	err := s.values(s.pkg, codeFile, file)
@@ -238,6 +619,47 @@ func (s *Scanner) processFile(goFile string, file *ast.File) (codeFile *File, er
 	return
 }
 
+// typeElems 记录接口中类型约束的类型元素，例如 ~int | ~string。
+// ast.FileExports 会删除没有名称的类型元素，返回的函数用于按原来的顺序恢复
+func typeElems(file *ast.File) (restore func()) {
+	origins := make(map[*ast.FieldList][]*ast.Field)
+	ast.Inspect(
+		file, func(node ast.Node) bool {
+			it, ok := node.(*ast.InterfaceType)
+			if !ok || it.Methods == nil {
+				return true
+			}
+			for _, field := range it.Methods.List {
+				switch field.Type.(type) {
+				case *ast.BinaryExpr, *ast.UnaryExpr:
+					origins[it.Methods] = append([]*ast.Field(nil), it.Methods.List...)
+					return true
+				}
+			}
+			return true
+		},
+	)
+	return func() {
+		for list, origin := range origins {
+			kept := make(map[*ast.Field]bool, len(list.List))
+			for _, field := range list.List {
+				kept[field] = true
+			}
+			fields := make([]*ast.Field, 0, len(origin))
+			for _, field := range origin {
+				switch field.Type.(type) {
+				case *ast.BinaryExpr, *ast.UnaryExpr:
+					kept[field] = true
+				}
+				if kept[field] {
+					fields = append(fields, field)
+				}
+			}
+			list.List = fields
+		}
+	}
+}
+
 func (s *Scanner) values(pkg *Pkg, codeFile *File, node *ast.File) error {
 	for _, decl := range node.Decls {
 		switch dt := decl.(type) {
@@ -245,15 +667,11 @@ func (s *Scanner) values(pkg *Pkg, codeFile *File, node *ast.File) error {
 			for _, spec := range dt.Specs {
 				switch st := spec.(type) {
 				case *ast.ValueSpec:
-					var v *Value
-					v, err := s.parseValue(st)
+					vs, err := s.parseValue(dt.Tok, st)
 					if err != nil {
 						return err
 					}
-					if v == nil {
-						continue
-					}
-					codeFile.Values = append(codeFile.Values, v)
+					codeFile.Values = append(codeFile.Values, vs...)
 				}
 			}
 		}
@@ -330,6 +748,8 @@ func (s *Scanner) isExported(node ast.Node) bool {
 	var name string
 	switch n := node.(type) {
 	case *ast.File:
+		restore := typeElems(n)
+		defer restore()
 		return ast.FileExports(n)
 	case *ast.FuncDecl:
 		name = n.Name.Name
@@ -351,8 +771,34 @@ func (s *Scanner) isExported(node ast.Node) bool {
 func (s *Scanner) parseType(ts *ast.TypeSpec) (*Type, error) {
 	t := &Type{}
 	t.Name = ts.Name.Name
-	t.Doc = astutil.ParseComment(ts.Doc)
-	switch tp := ts.Type.(type) {
+	t.Pos = s.position(ts.Pos())
+	t.End = s.position(ts.End())
+	doc := ts.Doc
+	if doc == nil {
+		// 没有括号的声明，文档在 GenDecl 上
+		doc = s.docs[ts]
+	}
+	t.Doc = astutil.ParseComment(doc)
+	t.ParsedDoc = astutil.ParseDoc(doc)
+	t.Annotations = astutil.FindAnnotations(doc, s.options.annotationPrefixes...)
+	t.Alias = ts.Assign.IsValid()
+	underlying, err := astutil.FieldType(ts.Type)
+	if err != nil {
+		log.Printf("failed to parse type: %v error: %v", ts.Name.Name, err.Error())
+		return nil, err
+	}
+	t.Underlying = underlying
+	t.TypeParams, err = s.parseFieldList(ts.TypeParams)
+	if err != nil {
+		log.Printf("failed to parse type params of type: %v error: %v", ts.Name.Name, err.Error())
+		return nil, err
+	}
+
+	expr := ts.Type
+	for paren, ok := expr.(*ast.ParenExpr); ok; paren, ok = expr.(*ast.ParenExpr) {
+		expr = paren.X
+	}
+	switch tp := expr.(type) {
 	case *ast.StructType:
 		fields, err := s.parseStruct(tp)
 		if err != nil {
@@ -361,9 +807,65 @@ func (s *Scanner) parseType(ts *ast.TypeSpec) (*Type, error) {
 		}
 		t.Fields = fields
 		t.Type = TypeStruct
-	case *ast.Ident:
-		// 可能是继承其他类型
-		t.Type = TypeT(tp.Name)
+	case *ast.ChanType:
+		elem, err := astutil.FieldType(tp.Value)
+		if err != nil {
+			log.Printf("failed to parse type: %v error: %v", ts.Name.Name, err.Error())
+			return nil, err
+		}
+		t.Type = TypeChan
+		t.Elem = elem
+		t.ChanDir = chanDir(tp.Dir)
+	case *ast.ArrayType:
+		elem, err := astutil.FieldType(tp.Elt)
+		if err != nil {
+			log.Printf("failed to parse type: %v error: %v", ts.Name.Name, err.Error())
+			return nil, err
+		}
+		t.Type = TypeArray
+		t.Elem = elem
+		if tp.Len != nil {
+			t.Len, err = astutil.ArrayLen(tp.Len)
+			if err != nil {
+				log.Printf("failed to parse type: %v error: %v", ts.Name.Name, err.Error())
+				return nil, err
+			}
+		}
+	case *ast.MapType:
+		key, err := astutil.FieldType(tp.Key)
+		if err != nil {
+			log.Printf("failed to parse type: %v error: %v", ts.Name.Name, err.Error())
+			return nil, err
+		}
+		elem, err := astutil.FieldType(tp.Value)
+		if err != nil {
+			log.Printf("failed to parse type: %v error: %v", ts.Name.Name, err.Error())
+			return nil, err
+		}
+		t.Type = TypeMap
+		t.Key = key
+		t.Elem = elem
+	case *ast.FuncType:
+		signature, err := s.parseFuncType(ts.Name.Name, tp)
+		if err != nil {
+			log.Printf("failed to parse type: %v error: %v", ts.Name.Name, err.Error())
+			return nil, err
+		}
+		t.Type = TypeFunc
+		t.Signature = signature
+	case *ast.InterfaceType:
+		t.Type = TypeInterface
+		err := s.parseInterface(t, tp)
+		if err != nil {
+			log.Printf("failed to parse type: %v error: %v", ts.Name.Name, err.Error())
+			return nil, err
+		}
+	case *ast.StarExpr:
+		t.Type = TypePointer
+		t.Elem = underlying[1:]
+	case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		// 可能是继承其他类型，或者是实例化的泛型类型，例如 List[int]
+		t.Type = TypeT(underlying)
 	default:
 		// log.Printf("unsupported type %T", ts.Type)
 		return nil, astutil.NewUnsupportedTypeError(ts.Type)
@@ -371,6 +873,53 @@ func (s *Scanner) parseType(ts *ast.TypeSpec) (*Type, error) {
 	return t, nil
 }
 
+// parseInterface 解析接口的方法列表，内嵌接口的方法会在所有文件扫描完成后再合并
+func (s *Scanner) parseInterface(t *Type, it *ast.InterfaceType) error {
+	for _, field := range it.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			embed, err := astutil.FieldType(field.Type)
+			if err != nil {
+				return err
+			}
+			t.Embeds = append(t.Embeds, embed)
+			switch field.Type.(type) {
+			case *ast.BinaryExpr, *ast.UnaryExpr:
+				// 类型约束中的类型元素，例如 ~int | ~string，不提供方法
+			default:
+				s.embeds[t] = append(s.embeds[t], field.Type)
+			}
+			continue
+		}
+		name := field.Names[0].Name
+		if s.options.onlyExported && !ast.IsExported(name) {
+			continue
+		}
+		method, err := s.parseFuncType(name, ft)
+		if err != nil {
+			return err
+		}
+		method.Pos = s.position(field.Pos())
+		method.End = s.position(field.End())
+		method.Doc = astutil.ParseComment(field.Doc)
+		method.ParsedDoc = astutil.ParseDoc(field.Doc)
+		method.Annotations = astutil.FindAnnotations(field.Doc, s.options.annotationPrefixes...)
+		t.Methods = append(t.Methods, method)
+	}
+	return nil
+}
+
+func chanDir(dir ast.ChanDir) ChanDir {
+	switch dir {
+	case ast.SEND:
+		return ChanSend
+	case ast.RECV:
+		return ChanRecv
+	default:
+		return ChanBoth
+	}
+}
+
 func (s *Scanner) parseStruct(st *ast.StructType) ([]*Field, error) {
 	fields := make([]*Field, 0, len(st.Fields.List))
 	for _, field := range st.Fields.List {
@@ -378,24 +927,44 @@ func (s *Scanner) parseStruct(st *ast.StructType) ([]*Field, error) {
 		if err != nil {
 			return nil, err
 		}
+		if len(field.Names) == 0 {
+			f[0].Embedded = true
+			f[0].Name = embeddedName(f[0].Type)
+			s.embedFields[f[0]] = field.Type
+		}
 		fields = append(fields, f...)
 	}
 	return fields, nil
 }
 
+// embeddedName 内嵌字段的字段名，即去掉指针、包名和泛型参数后的类型名，例如 *pkg.List[T] -> List
+func embeddedName(typ string) string {
+	name := typeName(typ)
+	if index := strings.LastIndex(name, "."); index >= 0 {
+		name = name[index+1:]
+	}
+	return name
+}
+
 func (s *Scanner) parseImport(is *ast.ImportSpec) (*Import, error) {
 	i := &Import{}
 	if is.Name != nil {
 		i.Name = is.Name.Name
 	}
 	i.Value = is.Path.Value
+	i.Pos = s.position(is.Pos())
+	i.End = s.position(is.End())
 	return i, nil
 }
 
 func (s *Scanner) parseFunc(fd *ast.FuncDecl) (*Func, error) {
 	codeFunc := &Func{}
 	codeFunc.Name = fd.Name.Name
+	codeFunc.Pos = s.position(fd.Pos())
+	codeFunc.End = s.position(fd.End())
 	codeFunc.Doc = astutil.ParseComment(fd.Doc)
+	codeFunc.ParsedDoc = astutil.ParseDoc(fd.Doc)
+	codeFunc.Annotations = astutil.FindAnnotations(fd.Doc, s.options.annotationPrefixes...)
 
 	if fd.Recv != nil {
 		if len(fd.Recv.List) != 1 {
@@ -413,17 +982,39 @@ func (s *Scanner) parseFunc(fd *ast.FuncDecl) (*Func, error) {
 				return nil, err
 			}
 			codeFunc.Receiver = f[0]
+			codeFunc.PointerReceiver = strings.HasPrefix(f[0].Type, "*")
 		}
 	}
 
-	funcType := fd.Type
+	signature, err := s.parseFuncType(fd.Name.Name, fd.Type)
+	if err != nil {
+		return nil, err
+	}
+	codeFunc.Params = signature.Params
+	codeFunc.Results = signature.Results
+	codeFunc.TypeParams = signature.TypeParams
+
+	return codeFunc, nil
+}
+
+// parseFuncType 解析函数的参数和响应列表
+func (s *Scanner) parseFuncType(name string, funcType *ast.FuncType) (*Func, error) {
+	codeFunc := &Func{
+		Name: name,
+	}
+	typeParams, err := s.parseFieldList(funcType.TypeParams)
+	if err != nil {
+		log.Printf("failed to parse type params of func: %v error: %v", name, err.Error())
+		return nil, err
+	}
+	codeFunc.TypeParams = typeParams
 	if funcType.Params != nil {
 		for _, field := range funcType.Params.List {
 			codeField, err := s.parseField(field)
 			if err != nil {
 				log.Printf(
 					"failed to parse params field: %#v of func: %v error: %v", field,
-					fd.Name.Name, err.Error(),
+					name, err.Error(),
 				)
 				return nil, err
 			}
@@ -437,22 +1028,40 @@ func (s *Scanner) parseFunc(fd *ast.FuncDecl) (*Func, error) {
 			if err != nil {
 				log.Printf(
 					"failed to parse results field: %#v of func: %v error: %v", field,
-					fd.Name.Name, err.Error(),
+					name, err.Error(),
 				)
 				return nil, err
 			}
 			codeFunc.Results = append(codeFunc.Results, codeField...)
 		}
 	}
-
 	return codeFunc, nil
 }
 
+// parseFieldList 解析字段列表，列表为nil时返回nil，例如没有类型参数的类型和函数
+func (s *Scanner) parseFieldList(list *ast.FieldList) ([]*Field, error) {
+	if list == nil {
+		return nil, nil
+	}
+	fields := make([]*Field, 0, list.NumFields())
+	for _, field := range list.List {
+		f, err := s.parseField(field)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f...)
+	}
+	return fields, nil
+}
+
 func (s *Scanner) parseField(field *ast.Field) ([]*Field, error) {
 	fields := make([]*Field, 0)
 
 	f := &Field{}
+	f.Pos = s.position(field.Pos())
+	f.End = s.position(field.End())
 	f.Doc = astutil.ParseComment(field.Doc)
+	f.ParsedDoc = astutil.ParseDoc(field.Doc)
 	ft, err := astutil.FieldType(field.Type)
 	if err != nil {
 		if len(field.Names) > 0 {
@@ -463,10 +1072,25 @@ func (s *Scanner) parseField(field *ast.Field) ([]*Field, error) {
 		return nil, err
 	}
 	f.Type = ft
+	if field.Tag != nil {
+		f.Tag, err = strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			log.Printf("failed to unquote tag: %v error: %v", field.Tag.Value, err.Error())
+			return nil, err
+		}
+		var tagErr error
+		f.Tags, tagErr = ParseTag(f.Tag)
+		if tagErr != nil {
+			// 标签格式错误不影响扫描，记录错误即可
+			log.Printf("failed to parse tag: %v error: %v", f.Tag, tagErr.Error())
+			s.pkg.Errors = append(s.pkg.Errors, tagErr)
+		}
+	}
 	if len(field.Names) > 0 {
 		for _, name := range field.Names {
 			nf := *f
 			nf.Name = name.Name
+			nf.Pos = s.position(name.Pos())
 			fields = append(fields, &nf)
 		}
 		return fields, err
@@ -476,36 +1100,144 @@ func (s *Scanner) parseField(field *ast.Field) ([]*Field, error) {
 	return fields, nil
 }
 
-func (s *Scanner) parseValue(valueSpec *ast.ValueSpec) (*Value, error) {
-	v := &Value{}
-	if len(valueSpec.Names) == 0 {
-		log.Fatal("value names must gt 0")
+// parseValue 解析声明的每个变量，例如 var a, b = 1, 2 解析为 a 和 b 两个变量
+func (s *Scanner) parseValue(tok token.Token, valueSpec *ast.ValueSpec) ([]*Value, error) {
+	names, ok := s.valueNames[valueSpec]
+	if !ok {
+		names = valueSpec.Names
 	}
-	v.Name = valueSpec.Names[0].Name
-	if !ast.IsExported(v.Name) {
-		return nil, nil
+	if len(names) == 0 {
+		log.Fatal("value names must gt 0")
 	}
 
-	v.Doc = astutil.ParseComment(valueSpec.Doc)
+	doc := valueSpec.Doc
+	if doc == nil {
+		// 没有括号的声明，文档在 GenDecl 上
+		doc = s.docs[valueSpec]
+	}
 
-	var vs *astutil.ValueSpec
+	var declaredType string
 	var err error
 	if valueSpec.Type != nil {
-		vs, err = astutil.ParseValue(valueSpec.Type)
+		declaredType, err = astutil.FieldType(valueSpec.Type)
 		if err != nil {
 			log.Printf("failed to parse type, value_spec: %T, err: %v", valueSpec, err)
 			return nil, err
 		}
-	} else {
-		vv := valueSpec.Values[0]
-		vs, err = astutil.ParseValue(vv)
-		if err != nil {
-			log.Printf("failed to parse type, value_spec: %T, err: %v", valueSpec, err)
-			return nil, err
+	}
+	kind := ValueVar
+	if tok == token.CONST {
+		kind = ValueConst
+	}
+
+	values := make([]*Value, 0, len(names))
+	for i, name := range names {
+		if !s.isValueExported(name.Name) {
+			continue
+		}
+		v := &Value{
+			Pos:          s.position(name.Pos()),
+			End:          s.position(name.End()),
+			Name:         name.Name,
+			Kind:         kind,
+			DeclaredType: declaredType,
+			Doc:          astutil.ParseComment(doc),
+			ParsedDoc:    astutil.ParseDoc(doc),
+		}
+
+		// 多个变量对应一个多返回值的调用时，例如 var a, b = f()，每个变量都使用这个调用
+		var expr ast.Expr
+		if len(valueSpec.Values) == len(names) {
+			expr = valueSpec.Values[i]
+		} else if len(valueSpec.Values) == 1 {
+			expr = valueSpec.Values[0]
+		}
+		if expr != nil {
+			v.End = s.position(expr.End())
+		} else if valueSpec.Type != nil {
+			v.End = s.position(valueSpec.Type.End())
+		}
+		vs := &astutil.ValueSpec{}
+		if expr != nil {
+			vs, err = astutil.ParseValue(expr)
+			if err != nil {
+				log.Printf("failed to parse type, value_spec: %T, err: %v", valueSpec, err)
+				return nil, err
+			}
+			v.ValueExpr, err = astutil.ParseExprTree(expr)
+			if err != nil {
+				log.Printf("failed to parse value expr, value_spec: %T, err: %v", valueSpec, err)
+				return nil, err
+			}
+			if len(valueSpec.Values) != len(names) {
+				// 多返回值的调用，值的类型从类型信息获取
+				vs.Type = ""
+			}
+			if tok == token.CONST && usesIota(expr) {
+				// iota 的值取决于所在的 Spec，值从类型信息获取，例如 KindA = iota 的值为 0
+				vs.Value = ""
+			}
+		}
+		if declaredType != "" {
+			// 声明的类型优先
+			vs.Type = declaredType
 		}
+		// 省略了类型和值的常量，例如 iota 序列，类型和值从类型信息获取
+
+		v.Value = vs.Value
+		v.Type = vs.Type
+		s.evalValue(v, name)
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// usesIota 表达式中是否使用了 iota
+func usesIota(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(
+		expr, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok && ident.Name == "iota" {
+				found = true
+			}
+			return !found
+		},
+	)
+	return found
+}
+
+// isValueExported 是否导出变量，只导出大写开头的变量，除非使用了 WithUnexportedValues
+func (s *Scanner) isValueExported(name string) bool {
+	if name == "_" {
+		return false
+	}
+	if s.options.onlyExported || !s.options.unexportedValues {
+		return ast.IsExported(name)
 	}
+	return true
+}
 
-	v.Value = vs.Value
-	v.Type = vs.Type
-	return v, nil
+// evalValue 根据类型信息补全变量的类型，以及常量的精确值
+func (s *Scanner) evalValue(v *Value, name *ast.Ident) {
+	info := s.pkg.p.TypesInfo
+	if info == nil {
+		return
+	}
+	obj := info.Defs[name]
+	if obj == nil {
+		return
+	}
+	v.ResolvedType = types.TypeString(obj.Type(), s.qualifier)
+	if v.Type == "" {
+		v.Type = v.ResolvedType
+	}
+	c, ok := obj.(*types.Const)
+	if !ok {
+		return
+	}
+	v.ExactValue = c.Val().ExactString()
+	if v.Value == "" {
+		// 浮点数的精确值是分数，可读的值使用 3.14 这样的格式
+		v.Value = c.Val().String()
+	}
 }
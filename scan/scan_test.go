@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/pjoc-team/ast/astutil"
@@ -12,11 +16,14 @@ import (
 
 // TestScanPkg test
 func TestScanPkg(t *testing.T) {
-	packages := astutil.ParsePackage(
+	packages, err := astutil.ParsePackage(
 		[]string{
 			"strconv",
 		}, nil,
 	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 	if len(packages) == 0 {
 		t.Fatal("no package")
 	}
@@ -40,11 +47,14 @@ func TestScanPkg(t *testing.T) {
 
 // TestScanPkgComponent test
 func TestScanPkgComponent(t *testing.T) {
-	packages := astutil.ParsePackage(
+	packages, err := astutil.ParsePackage(
 		[]string{
 			"pattern=../astutil/...",
 		}, nil,
 	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 	if len(packages) == 0 {
 		t.Fatal("no package")
 	}
@@ -68,7 +78,10 @@ func TestScanPkgComponent(t *testing.T) {
 
 // TestPath test
 func TestPath(t *testing.T) {
-	packages := astutil.ParsePackage([]string{"."}, nil)
+	packages, err := astutil.ParsePackage([]string{"."}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 	if len(packages) == 0 {
 		t.Fatal("no package")
 	}
@@ -89,7 +102,10 @@ func TestPath(t *testing.T) {
 
 // ExampleScanPkg example for scan pkg
 func ExampleScanPkg() {
-	packages := astutil.ParsePackage([]string{".", "../component", "fmt", "ast"}, nil)
+	packages, err := astutil.ParsePackage([]string{".", "../component", "fmt", "ast"}, nil)
+	if err != nil {
+		log.Println(err.Error())
+	}
 	if len(packages) == 0 {
 		return
 	}
@@ -174,11 +190,14 @@ func TestImport_AliasName(t *testing.T) {
 }
 
 func TestScanPkgTestData(t *testing.T) {
-	packages := astutil.ParsePackage(
+	packages, err := astutil.ParsePackage(
 		[]string{
 			"pattern=./testdata",
 		}, nil,
 	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
 	if len(packages) == 0 {
 		t.Fatal("no package")
 	}
@@ -199,3 +218,1259 @@ func TestScanPkgTestData(t *testing.T) {
 		fmt.Println(prettyJson)
 	}
 }
+
+func scanTestData(t *testing.T) *Pkg {
+	packages, err := astutil.ParsePackage([]string{"pattern=./testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(packages) == 0 {
+		t.Fatal("no package")
+	}
+	pkg, err := ScanPkg(packages[0], WithOnlyExported(true))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return pkg
+}
+
+func findType(pkg *Pkg, name string) *Type {
+	for _, file := range pkg.Files {
+		for _, tp := range file.Types {
+			if tp.Name == name {
+				return tp
+			}
+		}
+	}
+	return nil
+}
+
+func TestScanPkgChanType(t *testing.T) {
+	pkg := scanTestData(t)
+	tests := []struct {
+		name    string
+		elem    string
+		chanDir ChanDir
+	}{
+		{
+			name:    "ChanType",
+			elem:    "string",
+			chanDir: ChanBoth,
+		},
+		{
+			name:    "ChanReceiverType",
+			elem:    "string",
+			chanDir: ChanRecv,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				tp := findType(pkg, tt.name)
+				if tp == nil {
+					t.Fatalf("not found type: %v", tt.name)
+				}
+				if tp.Type != TypeChan || tp.Elem != tt.elem || tp.ChanDir != tt.chanDir {
+					t.Errorf(
+						"type = %v elem = %v dir = %v, want %v %v %v", tp.Type, tp.Elem,
+						tp.ChanDir, TypeChan, tt.elem, tt.chanDir,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestScanPkgNamedTypes(t *testing.T) {
+	pkg := scanTestData(t)
+	tests := []struct {
+		name string
+		want Type
+	}{
+		{
+			name: "MapType",
+			want: Type{Type: TypeMap, Key: "string", Elem: "*StructType"},
+		},
+		{
+			name: "ArrayType",
+			want: Type{Type: TypeArray, Elem: "int", Len: "4"},
+		},
+		{
+			name: "SliceType",
+			want: Type{Type: TypeArray, Elem: "StructType"},
+		},
+		{
+			name: "InterfaceType",
+			want: Type{Type: TypeInterface, Underlying: "interface {Do(string) error}"},
+		},
+		{
+			name: "ReadDoer",
+			want: Type{Type: TypeInterface, Underlying: "interface {InterfaceType; io.Reader; Close() error}"},
+		},
+		{
+			name: "AliasType",
+			want: Type{Type: "StringType", Alias: true},
+		},
+		{
+			name: "PointerType",
+			want: Type{Type: TypePointer, Elem: "StructType"},
+		},
+		{
+			name: "StringType",
+			want: Type{Type: "string"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				tp := findType(pkg, tt.name)
+				if tp == nil {
+					t.Fatalf("not found type: %v", tt.name)
+				}
+				if tp.Type != tt.want.Type || tp.Key != tt.want.Key || tp.Elem != tt.want.Elem ||
+					tp.Len != tt.want.Len || tp.Alias != tt.want.Alias {
+					t.Errorf("type = %#v, want %#v", tp, tt.want)
+				}
+				if tt.want.Underlying != "" && tp.Underlying != tt.want.Underlying {
+					t.Errorf("underlying = %v, want %v", tp.Underlying, tt.want.Underlying)
+				}
+			},
+		)
+	}
+}
+
+func TestScanPkgFuncType(t *testing.T) {
+	pkg := scanTestData(t)
+	tp := findType(pkg, "FuncType")
+	if tp == nil {
+		t.Fatal("not found type: FuncType")
+	}
+	if tp.Type != TypeFunc || tp.Signature == nil {
+		t.Fatalf("type = %v signature = %v", tp.Type, tp.Signature)
+	}
+	if tp.Underlying != "func(string,...int) (string,error)" {
+		t.Errorf("underlying = %v", tp.Underlying)
+	}
+	params := tp.Signature.Params
+	if len(params) != 2 || params[0].Name != "param1" || params[1].Type != "...int" {
+		t.Errorf("params = %v", params)
+	}
+	results := tp.Signature.Results
+	if len(results) != 2 || results[0].Name != "ret" || results[1].Type != "error" {
+		t.Errorf("results = %v", results)
+	}
+}
+
+func TestScanPkgInterfaceMethods(t *testing.T) {
+	pkg := scanTestData(t)
+	tp := findType(pkg, "ReadDoer")
+	if tp == nil {
+		t.Fatal("not found type: ReadDoer")
+	}
+	if len(tp.Embeds) != 2 || tp.Embeds[0] != "InterfaceType" || tp.Embeds[1] != "io.Reader" {
+		t.Errorf("embeds = %v", tp.Embeds)
+	}
+	want := []string{"Close", "Do", "Read"}
+	if len(tp.Methods) != len(want) {
+		t.Fatalf("methods = %v, want %v", len(tp.Methods), want)
+	}
+	for i, name := range want {
+		method := tp.Methods[i]
+		if method.Name != name {
+			t.Errorf("method[%d] = %v, want %v", i, method.Name, name)
+		}
+		path := Path{pkg.ID, "iface.go", "ReadDoer", name}
+		if method.Path.String() != path.String() {
+			t.Errorf("path = %v, want %v", method.Path, path)
+		}
+		if found, ok := pkg.FindPath(path); !ok || found != method {
+			t.Errorf("not found method of path: %v", path)
+		}
+	}
+	if tp.Methods[0].Doc != "Close close the doer" {
+		t.Errorf("doc = %v", tp.Methods[0].Doc)
+	}
+	read := tp.Methods[2]
+	if len(read.Params) != 1 || read.Params[0].Type != "[]byte" ||
+		len(read.Results) != 2 || read.Results[1].Type != "error" {
+		t.Errorf("read = %v %v", read.Params, read.Results)
+	}
+}
+
+func TestPkg_MethodsOf(t *testing.T) {
+	pkg := scanTestData(t)
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{
+			name: "StructType",
+			want: []string{"Value"},
+		},
+		{
+			name: "*StructType",
+			want: []string{"Value", "SetValue"},
+		},
+		{
+			name: "InterfaceType",
+			want: []string{"Do"},
+		},
+		{
+			name: "NotExists",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				methods := pkg.MethodsOf(tt.name)
+				got := make([]string, 0, len(methods))
+				for _, method := range methods {
+					got = append(got, method.Name)
+				}
+				if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+					t.Errorf("MethodsOf() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+
+	tp := findType(pkg, "StructType")
+	if len(tp.Methods) != 2 || tp.Methods[0].PointerReceiver || !tp.Methods[1].PointerReceiver {
+		t.Errorf("methods = %v", tp.Methods)
+	}
+	path := Path{pkg.ID, "methods.go", "*StructType.SetValue"}
+	if found, ok := pkg.FindPath(path); !ok || found != tp.Methods[1] {
+		t.Errorf("not found method of path: %v", path)
+	}
+}
+
+func TestScanPkgGenerics(t *testing.T) {
+	pkg := scanTestData(t)
+	tests := []struct {
+		name       string
+		typ        TypeT
+		underlying string
+		typeParams []string
+		embeds     []string
+	}{
+		{
+			name:       "Number",
+			typ:        TypeInterface,
+			underlying: "interface {~int | ~int64 | float64}",
+			embeds:     []string{"~int | ~int64 | float64"},
+		},
+		{
+			name:       "List",
+			typ:        TypeStruct,
+			underlying: "struct {Items []T}",
+			typeParams: []string{"T any"},
+		},
+		{
+			name:       "Pair",
+			typ:        TypeStruct,
+			underlying: "struct {Key K; Value V}",
+			typeParams: []string{"K comparable", "V any"},
+		},
+		{
+			name:       "IntList",
+			typ:        "List[int]",
+			underlying: "List[int]",
+		},
+		{
+			name:       "StringPairs",
+			typ:        TypeArray,
+			underlying: "[]Pair[string, int]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				tp := findType(pkg, tt.name)
+				if tp == nil {
+					t.Fatalf("not found type: %v", tt.name)
+				}
+				if tp.Type != tt.typ || tp.Underlying != tt.underlying {
+					t.Errorf("type = %v underlying = %v, want %v %v", tp.Type, tp.Underlying, tt.typ, tt.underlying)
+				}
+				if got := fieldsString(tp.TypeParams); got != strings.Join(tt.typeParams, ", ") {
+					t.Errorf("type params = %v, want %v", got, tt.typeParams)
+				}
+				if strings.Join(tp.Embeds, ", ") != strings.Join(tt.embeds, ", ") {
+					t.Errorf("embeds = %v, want %v", tp.Embeds, tt.embeds)
+				}
+			},
+		)
+	}
+
+	if methods := pkg.MethodsOf("*List"); len(methods) != 1 || methods[0].Name != "Push" {
+		t.Errorf("methods of *List = %v", methods)
+	}
+	var sum *Func
+	for _, file := range pkg.Files {
+		for _, f := range file.Funcs {
+			if f.Name == "Sum" {
+				sum = f
+			}
+		}
+	}
+	if sum == nil {
+		t.Fatal("not found func: Sum")
+	}
+	if got := fieldsString(sum.TypeParams); got != "T Number" {
+		t.Errorf("type params of Sum = %v", got)
+	}
+	if got := fieldsString(sum.Params); got != "values ...T" {
+		t.Errorf("params of Sum = %v", got)
+	}
+}
+
+// fieldsString 打印字段名和类型，例如 K comparable, V any
+func fieldsString(fields []*Field) string {
+	ss := make([]string, 0, len(fields))
+	for _, field := range fields {
+		ss = append(ss, field.Name+" "+field.Type)
+	}
+	return strings.Join(ss, ", ")
+}
+
+func TestScanPkgParsedDoc(t *testing.T) {
+	pkg := scanTestData(t)
+	tp := findType(pkg, "Documented")
+	if tp == nil {
+		t.Fatal("not found type: Documented")
+	}
+	doc := tp.ParsedDoc
+	if doc == nil {
+		t.Fatal("no parsed doc")
+	}
+	if doc.Summary != "Documented type with a structured doc." {
+		t.Errorf("summary = %v", doc.Summary)
+	}
+	if doc.Deprecated != "use StructType instead." {
+		t.Errorf("deprecated = %v", doc.Deprecated)
+	}
+	if len(doc.CodeBlocks) != 1 || doc.CodeBlocks[0] != "doc := Documented{}\ndoc.Name = \"name\"" {
+		t.Errorf("code blocks = %q", doc.CodeBlocks)
+	}
+	if table, ok := doc.Annotation("table"); !ok || table != "documented" {
+		t.Errorf("annotation table = %v", table)
+	}
+	if _, ok := doc.Directive("nolint:unused"); !ok {
+		t.Errorf("directives = %v", doc.Directives)
+	}
+	if !strings.HasPrefix(tp.Doc, "Documented type with a structured doc.") {
+		t.Errorf("doc = %v", tp.Doc)
+	}
+	if len(tp.Fields) != 1 || tp.Fields[0].ParsedDoc == nil {
+		t.Fatalf("fields = %v", tp.Fields)
+	}
+	if column, ok := tp.Fields[0].ParsedDoc.Annotation("column"); !ok || column != "name" {
+		t.Errorf("annotation column = %v", column)
+	}
+}
+
+func TestScanPkgAnnotations(t *testing.T) {
+	packages, err := astutil.ParsePackage([]string{"pattern=./testdata"}, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(packages) == 0 {
+		t.Fatal("no package")
+	}
+	pkg, err := ScanPkg(packages[0], WithOnlyExported(true), WithAnnotationPrefixes("@compose"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	service := findType(pkg, "Service")
+	if service == nil {
+		t.Fatal("not found type: Service")
+	}
+	if len(service.Annotations) != 1 || service.Annotations[0] != "@compose" {
+		t.Errorf("annotations of Service = %v", service.Annotations)
+	}
+	funcs := make(map[string]*Func)
+	for _, file := range pkg.Files {
+		for _, f := range file.Funcs {
+			funcs[f.Name] = f
+		}
+	}
+	if !funcs["Approved"].HasAnnotation("@compose") ||
+		funcs["Approved"].Annotations[0] != "@compose name=approved" {
+		t.Errorf("annotations of Approved = %v", funcs["Approved"].Annotations)
+	}
+	if funcs["NotApproved"].HasAnnotation("@compose") || funcs["Call"].HasAnnotation("@compose") {
+		t.Errorf("unexpected annotations")
+	}
+}
+
+func TestScanSource(t *testing.T) {
+	pkg, err := ScanSource(
+		"example.com/mem", map[string]string{
+			"mem.go": "package mem\n\n// Mem in-memory type\ntype Mem struct {\n\tName string\n}\n\n// Get get\nfunc (m *Mem) Get() string { return m.Name }\n",
+		},
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkg.Errors) > 0 {
+		t.Errorf("errors = %v", pkg.Errors)
+	}
+	tp := findType(pkg, "Mem")
+	if tp == nil || len(tp.Fields) != 1 || len(tp.Methods) != 1 {
+		t.Fatalf("type = %#v", tp)
+	}
+	if _, ok := pkg.FindPath(Path{"example.com/mem", "mem.go", "Mem", "Name"}); !ok {
+		t.Errorf("not found path of field")
+	}
+
+	pkg, err = ScanSource("example.com/mem", map[string]string{"mem.go": "package mem\n\nvar A int = \"a\"\n"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkg.Errors) == 0 {
+		t.Errorf("want type errors")
+	}
+
+	if _, err = ScanSource("example.com/mem", map[string]string{"mem.go": "mem"}); err == nil {
+		t.Errorf("want syntax error")
+	}
+}
+
+func TestScanPkgConstValues(t *testing.T) {
+	pkg := scanTestData(t)
+	values := make(map[string]*Value)
+	for _, file := range pkg.Files {
+		for _, v := range file.Values {
+			values[v.Name] = v
+		}
+	}
+	tests := []struct {
+		name         string
+		typ          string
+		value        string
+		resolvedType string
+		exactValue   string
+	}{
+		{
+			name:         "KindA",
+			typ:          "Kind",
+			value:        "0",
+			resolvedType: "Kind",
+			exactValue:   "0",
+		},
+		{
+			name:         "KindC",
+			typ:          "Kind",
+			value:        "2",
+			resolvedType: "Kind",
+			exactValue:   "2",
+		},
+		{
+			name:         "Size",
+			typ:          "untyped int",
+			value:        "1 << 10 * 4",
+			resolvedType: "untyped int",
+			exactValue:   "4096",
+		},
+		{
+			name:         "Pi",
+			typ:          "float64",
+			value:        "3.14",
+			resolvedType: "float64",
+			exactValue:   "7070651414971679/2251799813685248",
+		},
+		{
+			name:         "Greeting",
+			typ:          "untyped string",
+			value:        `"hello, " + "world"`,
+			resolvedType: "untyped string",
+			exactValue:   `"hello, world"`,
+		},
+		{
+			name:         "Paren",
+			typ:          "untyped int",
+			value:        "(Size + 1)",
+			resolvedType: "untyped int",
+			exactValue:   "4097",
+		},
+		{
+			name:         "StringConst",
+			typ:          "string",
+			value:        `"string const"`,
+			resolvedType: "untyped string",
+			exactValue:   `"string const"`,
+		},
+		{
+			name:         "Timeout",
+			typ:          "time.Duration",
+			value:        "time.Second * 2",
+			resolvedType: "time.Duration",
+		},
+		{
+			name:         "Ratio",
+			typ:          "untyped float",
+			value:        "2 * 1.5",
+			resolvedType: "untyped float",
+			exactValue:   "3",
+		},
+		{
+			name:         "StructVar",
+			typ:          "*StructType",
+			value:        "&StructType{}",
+			resolvedType: "*StructType",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				v, ok := values[tt.name]
+				if !ok {
+					t.Fatalf("not found value: %v", tt.name)
+				}
+				if v.Type != tt.typ || v.Value != tt.value || v.ResolvedType != tt.resolvedType ||
+					v.ExactValue != tt.exactValue {
+					t.Errorf(
+						"value = %v %v %v %v, want %v %v %v %v", v.Type, v.Value, v.ResolvedType,
+						v.ExactValue, tt.typ, tt.value, tt.resolvedType, tt.exactValue,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestScanPkgEnum(t *testing.T) {
+	pkg := scanTestData(t)
+	tp := findType(pkg, "Kind")
+	if tp == nil {
+		t.Fatal("not found type: Kind")
+	}
+	want := []EnumMember{
+		{Name: "KindA", Value: "0", Doc: "KindA first kind", exact: "0"},
+		{Name: "KindB", Value: "1", Doc: "KindB second kind", exact: "1"},
+		{Name: "KindC", Value: "2", Doc: "KindC third kind", exact: "2"},
+		{Name: "KindDefault", Value: "0", Doc: "KindDefault default kind, same as KindA", exact: "0"},
+	}
+	if len(tp.Enum) != len(want) {
+		t.Fatalf("len(enum) = %v want: %v", len(tp.Enum), len(want))
+	}
+	for i, member := range tp.Enum {
+		if *member != want[i] {
+			t.Errorf("enum[%d] = %#v want: %#v", i, *member, want[i])
+		}
+	}
+	if tp := findType(pkg, "Number"); tp == nil || tp.Enum != nil {
+		t.Errorf("type without constants should not be enum: %#v", tp)
+	}
+}
+
+func TestPkg_GenerateEnum(t *testing.T) {
+	source := map[string]string{
+		"status.go": "package pay\n\n// Status payment status\ntype Status int\n\nconst (\n" +
+			"\t_ Status = iota\n\t// StatusNew new\n\tStatusNew\n\tStatusPaid\n\tStatusDefault = StatusNew\n)\n\n" +
+			"// Currency currency\ntype Currency string\n\nconst CNY, USD Currency = \"cny\", \"usd\"\n",
+	}
+	pkg, err := ScanSource("example.com/pay", source)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	members := make([]string, 0)
+	for _, member := range findType(pkg, "Status").Enum {
+		members = append(members, member.Name)
+	}
+	// _ 不是枚举成员
+	if got := strings.Join(members, ","); got != "StatusNew,StatusPaid,StatusDefault" {
+		t.Errorf("enum = %v", got)
+	}
+	for _, typeName := range []string{"Status", "Currency"} {
+		code, err := pkg.GenerateEnum(typeName)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		for _, want := range []string{
+			"func (" + strings.ToLower(typeName[:1]) + " " + typeName + ") String() string",
+			"func Parse" + typeName + "(name string) (" + typeName + ", error)",
+			"MarshalJSON() ([]byte, error)",
+			"UnmarshalJSON(data []byte) error",
+		} {
+			if !strings.Contains(code, want) {
+				t.Errorf("code of %v not contains: %v\n%v", typeName, want, code)
+			}
+		}
+		source[strings.ToLower(typeName)+"_enum.go"] = code
+	}
+	if strings.Count(source["status_enum.go"], "return \"StatusNew\"") != 1 ||
+		!strings.Contains(source["status_enum.go"], "case \"StatusDefault\":") {
+		t.Errorf("duplicated values should share the first name:\n%v", source["status_enum.go"])
+	}
+
+	// 生成的代码需要能通过类型检查
+	if _, err = astutil.LoadSource("example.com/pay", source); err != nil {
+		t.Fatalf("failed to check types of generated code: %v", err)
+	}
+	pkg, err = ScanSource("example.com/pay", source)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if methods := pkg.MethodsOf("*Status"); len(methods) != 3 {
+		t.Errorf("len(methods) = %v want: 3", len(methods))
+	}
+
+	if _, err = pkg.GenerateEnum("NotFound"); err == nil {
+		t.Errorf("want error of not found type")
+	}
+
+	// 过长的字符串常量的 Value 会被截断，不能根据 Value 判断值是否相同
+	prefix := strings.Repeat("a", 90)
+	pkg, err = ScanSource(
+		"example.com/msg", map[string]string{
+			"msg.go": "package msg\n\n// Msg message\ntype Msg string\n\nconst (\n" +
+				"\tMsgA Msg = \"" + prefix + "-one\"\n\tMsgB Msg = \"" + prefix + "-two\"\n)\n",
+		},
+	)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	code, err := pkg.GenerateEnum("Msg")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !strings.Contains(code, "case MsgA:") || !strings.Contains(code, "case MsgB:") {
+		t.Errorf("long string members should not be deduplicated:\n%v", code)
+	}
+}
+
+func TestScanPkgMultiNameValues(t *testing.T) {
+	pkg := scanTestData(t)
+	values := make(map[string]*Value)
+	for _, file := range pkg.Files {
+		for _, v := range file.Values {
+			values[v.Name] = v
+		}
+	}
+	tests := []struct {
+		name         string
+		kind         ValueKind
+		typ          string
+		declaredType string
+		value        string
+	}{
+		{name: "Width", kind: ValueConst, typ: "int", value: "640"},
+		{name: "Height", kind: ValueConst, typ: "int", value: "480"},
+		{name: "Host", kind: ValueVar, typ: "string", value: `"localhost"`},
+		{name: "Port", kind: ValueVar, typ: "int", value: "8080"},
+		{name: "Left", kind: ValueVar, typ: "string", value: `split("left,right")`},
+		{name: "Right", kind: ValueVar, typ: "string", value: `split("left,right")`},
+		{name: "KindB", kind: ValueConst, typ: "Kind", value: "1"},
+		{name: "Pi", kind: ValueConst, typ: "float64", declaredType: "float64", value: "3.14"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				v, ok := values[tt.name]
+				if !ok {
+					t.Fatalf("not found value: %v", tt.name)
+				}
+				if v.Kind != tt.kind || v.Type != tt.typ || v.DeclaredType != tt.declaredType || v.Value != tt.value {
+					t.Errorf(
+						"value = %v %v %v %v, want %v %v %v %v", v.Kind, v.Type, v.DeclaredType, v.Value,
+						tt.kind, tt.typ, tt.declaredType, tt.value,
+					)
+				}
+			},
+		)
+	}
+	if _, ok := values["port"]; ok {
+		t.Errorf("unexported value should not be scanned")
+	}
+	if doc := values["Height"].Doc; doc != "Width multi-name constant" {
+		t.Errorf("doc = %v", doc)
+	}
+}
+
+func TestWithUnexportedValues(t *testing.T) {
+	source := map[string]string{"v.go": "package v\n\nvar a, B = 1, 2\n\nconst c = \"c\"\n"}
+	tests := []struct {
+		name string
+		opts []Option
+		want []string
+	}{
+		{name: "default", want: []string{"B"}},
+		{name: "unexported", opts: []Option{WithUnexportedValues(true)}, want: []string{"a", "B", "c"}},
+		{
+			name: "only exported",
+			opts: []Option{WithUnexportedValues(true), WithOnlyExported(true)},
+			want: []string{"B"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				pkg, err := ScanSource("example.com/v", source, tt.opts...)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				names := make([]string, 0)
+				for _, file := range pkg.Files {
+					for _, v := range file.Values {
+						names = append(names, v.Name)
+					}
+				}
+				if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+					t.Errorf("values = %v want: %v", names, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestWithFilter(t *testing.T) {
+	source := map[string]string{
+		"svc.go": "package svc\n\n// Service service\n// @compose\ntype Service struct {\n\tName string\n\tAge int\n}\n\n" +
+			"// Get get\nfunc (s *Service) Get() string { return s.Name }\n\n// Set set\nfunc (s *Service) Set(name string) { s.Name = name }\n\n" +
+			"// New new\n// @compose\nfunc New() *Service { return &Service{} }\n\n// Stop stop\nfunc (g Gen) Stop() {}\n\n" +
+			"// Version version\nvar Version = \"v1\"\n",
+		"svc_gen.go": "package svc\n\n// Gen generated\ntype Gen struct{}\n\n// Run run\nfunc (g Gen) Run() {}\n",
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{
+			name:   "name",
+			filter: Or(Not(KindFilter(EntityFunc)), NameFilter(regexp.MustCompile("^(Get|Run)$"))),
+			want: []string{
+				"svc.go", "svc.go.Version", "svc.go.*Service.Get", "svc.go.Service", "svc.go.Service.Name",
+				"svc.go.Service.Age", "svc_gen.go", "svc_gen.go.Gen.Run", "svc_gen.go.Gen",
+			},
+		},
+		{
+			name:   "receiver",
+			filter: ReceiverFilter("Service"),
+			want: []string{
+				"svc.go", "svc.go.Version", "svc.go.*Service.Get", "svc.go.*Service.Set", "svc.go.Service",
+				"svc.go.Service.Name", "svc.go.Service.Age", "svc_gen.go", "svc_gen.go.Gen",
+			},
+		},
+		{
+			name:   "annotation",
+			filter: Or(Not(KindFilter(EntityType, EntityFunc)), AnnotationFilter("@compose")),
+			want: []string{
+				"svc.go", "svc.go.Version", "svc.go.New", "svc.go.Service", "svc.go.Service.Name",
+				"svc.go.Service.Age", "svc_gen.go",
+			},
+		},
+		{
+			name:   "file",
+			filter: Not(FileFilter("*_gen.go")),
+			want: []string{
+				"svc.go", "svc.go.Version", "svc.go.*Service.Get", "svc.go.*Service.Set", "svc.go.New",
+				"svc.go.Service", "svc.go.Service.Name", "svc.go.Service.Age",
+			},
+		},
+		{
+			name:   "methods of rejected type",
+			filter: Not(And(KindFilter(EntityType), NameFilter(regexp.MustCompile("^Service$")))),
+			want: []string{
+				"svc.go", "svc.go.Version", "svc.go.New", "svc.go.Gen.Stop", "svc_gen.go", "svc_gen.go.Gen.Run",
+				"svc_gen.go.Gen",
+			},
+		},
+		{
+			name:   "kind",
+			filter: And(Not(KindFilter(EntityField, EntityValue)), Not(FileFilter("*_gen.go"))),
+			want: []string{
+				"svc.go", "svc.go.*Service.Get", "svc.go.*Service.Set", "svc.go.New", "svc.go.Service",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				pkg, err := ScanSource("example.com/svc", source, WithFilter(tt.filter), WithAnnotationPrefixes("@"))
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				got := make([]string, 0)
+				for _, file := range pkg.Files {
+					got = append(got, file.Name)
+					for _, v := range file.Values {
+						got = append(got, file.Name+"."+v.Name)
+					}
+					for _, f := range file.Funcs {
+						got = append(got, file.Name+"."+f.Path[len(f.Path)-1])
+					}
+					for _, tp := range file.Types {
+						got = append(got, file.Name+"."+tp.Name)
+						for _, field := range tp.Fields {
+							got = append(got, file.Name+"."+tp.Name+"."+field.Name)
+						}
+					}
+				}
+				if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+					t.Errorf("got = %v\nwant: %v", got, tt.want)
+				}
+				for _, tp := range pkg.Files[0].Types {
+					for _, method := range tp.Methods {
+						if _, ok := pkg.FindPath(method.Path); !ok {
+							t.Errorf("not found path of method: %v", method.Path)
+						}
+					}
+				}
+				// 文件没有注册查找路径
+				if want := len(got) - len(pkg.Files); want != len(pkg.PathAndTypes) {
+					t.Errorf("len(paths) = %v want: %v", len(pkg.PathAndTypes), want)
+				}
+			},
+		)
+	}
+}
+
+func TestWithOnlyExported(t *testing.T) {
+	source := map[string]string{"v.go": "package v\n\nfunc A() {}\n\nfunc b() {}\n"}
+	for _, onlyExported := range []bool{true, false} {
+		pkg, err := ScanSource("example.com/v", source, WithOnlyExported(onlyExported))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		want := 1
+		if !onlyExported {
+			want = 2
+		}
+		if got := len(pkg.Files[0].Funcs); got != want {
+			t.Errorf("onlyExported: %v len(funcs) = %v want: %v", onlyExported, got, want)
+		}
+	}
+}
+
+func TestScanPkgPositions(t *testing.T) {
+	src := "package pos\n\nimport \"io\"\n\n// T type\ntype T struct {\n\tA, B int\n}\n\n" +
+		"// Get get\nfunc (t *T) Get() int {\n\treturn t.A\n}\n\n// I interface\ntype I interface {\n\tio.Closer\n\tRun() error\n}\n\n" +
+		"var X, Y = 1, \"y\"\n"
+	pkg, err := ScanSource("example.com/pos", map[string]string{"pos.go": src})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(pkg.Errors) > 0 {
+		t.Fatalf("errors = %v", pkg.Errors)
+	}
+	file := pkg.Files[0]
+	tpT := findType(pkg, "T")
+	tpI := findType(pkg, "I")
+	values := make(map[string]*Value)
+	for _, v := range file.Values {
+		values[v.Name] = v
+	}
+	tests := []struct {
+		name     string
+		pos      *Position
+		end      *Position
+		wantPos  string
+		wantEnd  string
+		wantCode string
+	}{
+		{name: "file", pos: file.Pos, end: file.End, wantPos: "1:1", wantEnd: "21:18"},
+		{name: "import", pos: file.Imports[0].Pos, end: file.Imports[0].End, wantPos: "3:8", wantCode: `"io"`},
+		{name: "type", pos: tpT.Pos, end: tpT.End, wantPos: "6:6", wantCode: "T struct {\n\tA, B int\n}"},
+		{name: "field", pos: tpT.Fields[1].Pos, end: tpT.Fields[1].End, wantPos: "7:5", wantCode: "B int"},
+		{name: "func", pos: file.Funcs[0].Pos, end: file.Funcs[0].End, wantPos: "11:1", wantEnd: "13:2"},
+		{name: "method", pos: tpI.Methods[0].Pos, end: tpI.Methods[0].End, wantPos: "18:2", wantCode: "Run() error"},
+		{name: "value", pos: values["Y"].Pos, end: values["Y"].End, wantPos: "21:8", wantEnd: "21:18"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if tt.pos == nil || tt.end == nil {
+					t.Fatalf("pos = %v end = %v", tt.pos, tt.end)
+				}
+				if got := fmt.Sprintf("%d:%d", tt.pos.Line, tt.pos.Column); got != tt.wantPos {
+					t.Errorf("pos = %v want: %v", got, tt.wantPos)
+				}
+				if tt.wantEnd != "" {
+					if got := fmt.Sprintf("%d:%d", tt.end.Line, tt.end.Column); got != tt.wantEnd {
+						t.Errorf("end = %v want: %v", got, tt.wantEnd)
+					}
+				}
+				if tt.wantCode != "" {
+					if code := src[tt.pos.Offset:tt.end.Offset]; code != tt.wantCode {
+						t.Errorf("code = %q want: %q", code, tt.wantCode)
+					}
+				}
+				if !strings.HasSuffix(tt.pos.Filename, "pos.go") {
+					t.Errorf("filename = %v", tt.pos.Filename)
+				}
+			},
+		)
+	}
+	// 内嵌接口的方法根据类型信息得到，只有开始位置
+	closer := findMethod(tpI.Methods, "Close")
+	if closer == nil || closer.Pos == nil || closer.End != nil {
+		t.Errorf("method = %#v", closer)
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "json",
+			tag:  `json:"name,omitempty"`,
+			want: map[string]string{"json": "name [omitempty]"},
+		},
+		{
+			name: "multi",
+			tag:  `json:"-" yaml:"name"  validate:"required,min=1,max=10"`,
+			want: map[string]string{"json": "- []", "yaml": "name []", "validate": "required [min=1 max=10]"},
+		},
+		{
+			name: "escaped",
+			tag:  `db:"a\"b"`,
+			want: map[string]string{"db": `a"b []`},
+		},
+		{
+			name: "empty value",
+			tag:  `json:",omitempty"`,
+			want: map[string]string{"json": " [omitempty]"},
+		},
+		{
+			name: "duplicate key",
+			tag:  `json:"first" json:"second,omitempty"`,
+			want: map[string]string{"json": "first []"},
+		},
+		{
+			name:    "bad syntax",
+			tag:     `json:"name" yaml`,
+			want:    map[string]string{"json": "name []"},
+			wantErr: true,
+		},
+		{
+			name:    "unterminated",
+			tag:     `json:"name`,
+			want:    map[string]string{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				tags, err := ParseTag(tt.tag)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("ParseTag() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				got := make(map[string]string)
+				for key, tag := range tags {
+					if tag.Key != key {
+						t.Errorf("key = %v want: %v", tag.Key, key)
+					}
+					got[key] = fmt.Sprintf("%s %v", tag.Name, tag.Options)
+				}
+				if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+					t.Errorf("ParseTag() = %v, want %v", got, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestScanPkgFieldTags(t *testing.T) {
+	src := "package tags\n\n// User user\ntype User struct {\n" +
+		"\tID, No int `json:\"id,string\" db:\"id\"`\n" +
+		"\tName string \"json:\\\"name,omitempty\\\"\"\n" +
+		"\tAge int\n" +
+		"\tBad int `json`\n}\n"
+	pkg, err := ScanSource("example.com/tags", map[string]string{"tags.go": src})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	tp := findType(pkg, "User")
+	if tp == nil || len(tp.Fields) != 5 {
+		t.Fatalf("type = %#v", tp)
+	}
+	for _, field := range tp.Fields[:2] {
+		if field.Tag != `json:"id,string" db:"id"` {
+			t.Errorf("tag of %v = %v", field.Name, field.Tag)
+		}
+		if tag := field.Tags["json"]; tag == nil || tag.Name != "id" || !tag.HasOption("string") {
+			t.Errorf("json tag of %v = %#v", field.Name, tag)
+		}
+		if tag := field.Tags["db"]; tag == nil || tag.Value != "id" {
+			t.Errorf("db tag of %v = %#v", field.Name, tag)
+		}
+	}
+	name := tp.Fields[2]
+	if name.Tag != `json:"name,omitempty"` || name.Tags["json"].Name != "name" || !name.Tags["json"].HasOption("omitempty") {
+		t.Errorf("field = %#v", name)
+	}
+	if age := tp.Fields[3]; age.Tag != "" || age.Tags != nil {
+		t.Errorf("field = %#v", age)
+	}
+	if len(pkg.Errors) != 1 {
+		t.Errorf("want error of bad tag, errors = %v", pkg.Errors)
+	}
+}
+
+func TestScanPkgEmbeddedFields(t *testing.T) {
+	pkg := scanTestData(t)
+	tp := findType(pkg, "Request")
+	if tp == nil {
+		t.Fatal("not found type: Request")
+	}
+	embedded := make([]string, 0)
+	for _, field := range tp.Fields {
+		if field.Embedded {
+			embedded = append(embedded, field.Name+" "+field.Type)
+		}
+	}
+	if got := strings.Join(embedded, ","); got != "Base *Base,Audit Audit,Position token.Position" {
+		t.Errorf("embedded = %v", got)
+	}
+	if _, ok := pkg.FindPath(Path{pkg.ID, "embed.go", "Request", "Base"}); !ok {
+		t.Errorf("not found path of embedded field")
+	}
+
+	fieldSet := make([]string, 0)
+	for _, field := range tp.FieldSet {
+		fieldSet = append(fieldSet, strings.Join(append(field.Via, field.Field.Name), ".")+" "+field.Field.Type)
+	}
+	want := []string{
+		"Base *Base", "Audit Audit", "Position token.Position", "ID string", "Audit.At int64",
+		"Position.Filename string", "Position.Offset int", "Position.Line int", "Position.Column int",
+	}
+	if strings.Join(fieldSet, ",") != strings.Join(want, ",") {
+		t.Errorf("field set = %v\nwant: %v", fieldSet, want)
+	}
+	if strings.Join(tp.AmbiguousFields, ",") != "Name" {
+		t.Errorf("ambiguous fields = %v", tp.AmbiguousFields)
+	}
+	// 同个包的字段有查找路径，其他包的字段没有
+	at := tp.FieldSet[4].Field
+	if at.Path.String() != (Path{pkg.ID, "embed.go", "Audit", "At"}).String() {
+		t.Errorf("path = %v", at.Path)
+	}
+	if line := tp.FieldSet[7].Field; line.Path != nil || line.Pos == nil {
+		t.Errorf("field = %#v", line)
+	}
+}
+
+func TestScanPkgEmbeddedCycle(t *testing.T) {
+	src := "package cycle\n\n// A a\ntype A struct {\n\t*B\n\tX int\n}\n\n// B b\ntype B struct {\n\t*A\n\tY int\n}\n"
+	pkg, err := ScanSource("example.com/cycle", map[string]string{"cycle.go": src})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	fieldSet := make([]string, 0)
+	for _, field := range findType(pkg, "A").FieldSet {
+		fieldSet = append(fieldSet, strings.Join(append(field.Via, field.Field.Name), "."))
+	}
+	if got := strings.Join(fieldSet, ","); got != "B,X,B.A,B.Y" {
+		t.Errorf("field set = %v", got)
+	}
+}
+
+func TestScanPatterns(t *testing.T) {
+	m, err := ScanPatterns([]string{"./testdata/module/..."}, WithOnlyExported(true))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	const modelPath = "github.com/pjoc-team/ast/scan/testdata/module/model"
+	const servicePath = "github.com/pjoc-team/ast/scan/testdata/module/service"
+	if len(m.Packages) != 2 || m.Packages[0].PkgPath != modelPath || m.Packages[1].PkgPath != servicePath {
+		t.Fatalf("packages = %v", m.Packages)
+	}
+	for _, pkg := range m.Packages {
+		if len(pkg.Errors) > 0 {
+			t.Errorf("errors of %v = %v", pkg.PkgPath, pkg.Errors)
+		}
+	}
+	if _, ok := m.Package(servicePath); !ok {
+		t.Errorf("not found package: %v", servicePath)
+	}
+
+	user, ok := m.Lookup(modelPath, "User")
+	if !ok {
+		t.Fatal("not found symbol: User")
+	}
+	userPath := user.(*Type).Path
+	if found, ok := m.FindPath(userPath); !ok || found != user {
+		t.Errorf("FindPath(%v) = %v", userPath, found)
+	}
+	if _, ok := m.Lookup(servicePath, "*Service.Get"); !ok {
+		t.Errorf("not found symbol: *Service.Get")
+	}
+
+	svc, _ := m.Lookup(servicePath, "Service")
+	rolePath := Path{modelPath, "model.go", "Role"}
+	tests := []struct {
+		name  string
+		field *Field
+		want  Path
+	}{
+		{name: "map", field: svc.(*Type).Fields[0]},
+		{name: "pointer", field: svc.(*Type).Fields[1], want: userPath},
+		{name: "slice", field: svc.(*Type).Fields[2], want: userPath},
+		{name: "chan", field: svc.(*Type).Fields[3], want: rolePath},
+	}
+	get, _ := m.Lookup(servicePath, "*Service.Get")
+	tests = append(
+		tests, struct {
+			name  string
+			field *Field
+			want  Path
+		}{name: "result", field: get.(*Func).Results[0], want: userPath},
+		struct {
+			name  string
+			field *Field
+			want  Path
+		}{name: "param", field: get.(*Func).Params[0]},
+	)
+	getter, _ := m.Lookup(servicePath, "Getter")
+	tests = append(
+		tests, struct {
+			name  string
+			field *Field
+			want  Path
+		}{name: "interface method", field: getter.(*Type).Methods[0].Results[0], want: userPath},
+	)
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if tt.field.TypePath.String() != tt.want.String() {
+					t.Errorf("TypePath = %v want: %v", tt.field.TypePath, tt.want)
+				}
+			},
+		)
+	}
+}
+
+func TestRefTypeName(t *testing.T) {
+	tests := map[string]string{
+		"User":                 "User",
+		"*m.User":              "m.User",
+		"[]*m.User":            "m.User",
+		"[4]m.User":            "m.User",
+		"...m.User":            "m.User",
+		"chan (<-chan User)":   "User",
+		"chan<- *List[int]":    "List",
+		"map[string]*m.User":   "",
+		"func(int) error":      "",
+		"struct {Name string}": "",
+		"interface{}":          "",
+	}
+	for typ, want := range tests {
+		if got := refTypeName(typ); got != want {
+			t.Errorf("refTypeName(%v) = %v want: %v", typ, got, want)
+		}
+	}
+}
+
+func TestScanPkgConcurrency(t *testing.T) {
+	scanJSON := func(concurrency int) string {
+		packages, err := astutil.ParsePackage([]string{"pattern=./testdata"}, nil)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if len(packages) == 0 {
+			t.Fatal("no package")
+		}
+		pkg, err := ScanPkg(packages[0], WithOnlyExported(true), WithConcurrency(concurrency))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		prettyJSON, err := jsonutil.PrettyJson(pkg)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		paths := make([]string, 0, len(pkg.PathAndTypes))
+		for path := range pkg.PathAndTypes {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		return prettyJSON + strings.Join(paths, "\n")
+	}
+	want := scanJSON(1)
+	for _, concurrency := range []int{0, 4, 16} {
+		if got := scanJSON(concurrency); got != want {
+			t.Errorf("result of concurrency: %v is different from sequential scanning", concurrency)
+		}
+	}
+}
+
+func TestScanPatternsErrors(t *testing.T) {
+	dir, err := filepath.Abs("./testdata/module/model")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	overlay := map[string][]byte{
+		filepath.Join(dir, "broken.go"): []byte("package model\n\n// Broken type error\nvar Broken int = \"a\"\n"),
+	}
+	tests := []struct {
+		name string
+		opts []astutil.LoadOption
+	}{
+		{name: "check types when loading", opts: []astutil.LoadOption{astutil.WithOverlay(overlay)}},
+		{
+			name: "check types when scanning",
+			opts: []astutil.LoadOption{astutil.WithOverlay(overlay), astutil.WithCheckTypes(false)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				m, err := ScanPatterns(
+					[]string{"./testdata/module/..."}, WithOnlyExported(true), WithLoadOptions(tt.opts...),
+				)
+				if err != nil {
+					t.Fatal(err.Error())
+				}
+				model, ok := m.Package("github.com/pjoc-team/ast/scan/testdata/module/model")
+				if !ok {
+					t.Fatal("not found package: model")
+				}
+				// 每个错误只记录一次
+				if len(model.Errors) != 1 || !strings.Contains(model.Errors[0].Error(), "broken.go:4:") {
+					t.Errorf("errors = %v", model.Errors)
+				}
+			},
+		)
+	}
+}
+
+func TestScanPatternsConcurrency(t *testing.T) {
+	scanJSON := func(concurrency int) string {
+		m, err := ScanPatterns([]string{"./testdata/..."}, WithOnlyExported(true), WithConcurrency(concurrency))
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		prettyJSON, err := jsonutil.PrettyJson(m.Packages)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return prettyJSON
+	}
+	want := scanJSON(1)
+	if got := scanJSON(8); got != want {
+		t.Errorf("result of concurrency is different from sequential scanning")
+	}
+}
+
+func TestParallel(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 100} {
+		got := make([]int, 10)
+		parallel(
+			n, len(got), func(i int) {
+				got[i] = i * i
+			},
+		)
+		for i, v := range got {
+			if v != i*i {
+				t.Errorf("n: %v got[%d] = %v", n, i, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package scan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tag 结构体字段的标签，例如 `json:"name,omitempty"` 的 json
+type Tag struct {
+	// Key 标签名，例如 json
+	Key string `json:"key" yaml:"key"`
+
+	// Value 标签的原始值，例如 name,omitempty
+	Value string `json:"value" yaml:"value"`
+
+	// Name 第一个逗号前的部分，一般为序列化的名称，例如 name
+	Name string `json:"name" yaml:"name"`
+
+	// Options 逗号分隔的其他部分，例如 [omitempty]
+	Options []string `json:"options" yaml:"options"`
+}
+
+// HasOption 是否有该选项，例如 omitempty
+func (t *Tag) HasOption(option string) bool {
+	for _, o := range t.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTag 按 reflect.StructTag 的约定解析标签，例如 json:"name,omitempty" db:"name"，
+// key是标签名，重复的key与 reflect.StructTag.Get 一致使用第一个。标签格式错误时返回错误之前已经解析的标签
+func ParseTag(tag string) (map[string]*Tag, error) {
+	tags := make(map[string]*Tag)
+	for tag != "" {
+		// 跳过空格
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// 标签名是除了控制字符、空格、引号和冒号之外的字符
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return tags, fmt.Errorf("bad syntax for struct tag: %v", tag)
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+
+		// 带引号的值
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return tags, fmt.Errorf("bad syntax for struct tag value: %v", key)
+		}
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return tags, fmt.Errorf("bad syntax for struct tag value: %v error: %v", key, err.Error())
+		}
+		tag = tag[i+1:]
+
+		t := &Tag{
+			Key:   key,
+			Value: value,
+		}
+		parts := strings.Split(value, ",")
+		t.Name = parts[0]
+		if len(parts) > 1 {
+			t.Options = parts[1:]
+		}
+		if _, ok := tags[key]; !ok {
+			tags[key] = t
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,22 @@
+package testdata
+
+// Service annotated type
+// @compose
+type Service struct {
+	Name string
+}
+
+// Call method of the annotated type
+func (s *Service) Call() error {
+	return nil
+}
+
+// Approved annotated func
+// @compose name=approved
+func Approved(name string) string {
+	return name
+}
+
+// NotApproved func without annotation
+func NotApproved() {
+}
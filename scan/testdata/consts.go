@@ -0,0 +1,48 @@
+package testdata
+
+import "time"
+
+// Kind enum kind
+type Kind int
+
+const (
+	// KindA first kind
+	KindA Kind = iota
+	// KindB second kind
+	KindB
+	// KindC third kind
+	KindC
+)
+
+// KindDefault default kind, same as KindA
+const KindDefault = KindA
+
+const (
+	// Size constant expression
+	Size = 1 << 10 * 4
+	// Pi typed float constant
+	Pi float64 = 3.14
+	// Greeting constant string expression
+	Greeting = "hello, " + "world"
+	// Paren paren expression
+	Paren = (Size + 1)
+)
+
+// Width multi-name constant
+const Width, Height = 640, 480
+
+// Host multi-name variable with an unexported name
+var Host, port, Port = "localhost", 80, 8080
+
+// Left tuple assignment
+var Left, Right = split("left,right")
+
+func split(s string) (string, string) {
+	return s, s
+}
+
+// Timeout duration expression
+var Timeout = time.Second * 2
+
+// Ratio untyped float expression
+const Ratio = 2 * 1.5
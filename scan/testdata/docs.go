@@ -0,0 +1,19 @@
+package testdata
+
+// Documented type with a structured doc. It is used to test ParsedDoc.
+//
+// Details of the type.
+//
+//	doc := Documented{}
+//	doc.Name = "name"
+//
+// Deprecated: use StructType instead.
+//
+// @table documented
+//
+//nolint:unused
+type Documented struct {
+	// Name the name
+	// @column name
+	Name string
+}
@@ -0,0 +1,28 @@
+package testdata
+
+import "go/token"
+
+// Base base fields
+type Base struct {
+	// ID id
+	ID int64
+	// Name name
+	Name string
+}
+
+// Audit audit fields
+type Audit struct {
+	// Name name of auditor
+	Name string
+	// At audit time
+	At int64
+}
+
+// Request request embedding Base and Audit
+type Request struct {
+	*Base
+	Audit
+	token.Position
+	// ID id of request, shadows Base.ID
+	ID string
+}
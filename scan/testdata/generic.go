@@ -0,0 +1,37 @@
+package testdata
+
+// Number constraint with type elements
+type Number interface {
+	~int | ~int64 | float64
+}
+
+// List generic list
+type List[T any] struct {
+	Items []T
+}
+
+// Push pointer receiver of generic type
+func (l *List[T]) Push(item T) {
+	l.Items = append(l.Items, item)
+}
+
+// Pair generic type with multiple type params
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// IntList instantiated generic type
+type IntList = List[int]
+
+// StringPairs instantiated generic type with multiple type params
+type StringPairs []Pair[string, int]
+
+// Sum generic func
+func Sum[T Number](values ...T) T {
+	var sum T
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
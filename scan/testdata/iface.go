@@ -0,0 +1,12 @@
+package testdata
+
+import "io"
+
+// ReadDoer interface with embedded interfaces
+type ReadDoer interface {
+	InterfaceType
+	io.Reader
+
+	// Close close the doer
+	Close() error
+}
@@ -0,0 +1,10 @@
+package testdata
+
+// Value value receiver method of StructType
+func (s StructType) Value() string {
+	return ""
+}
+
+// SetValue pointer receiver method of StructType
+func (s *StructType) SetValue(v string) {
+}
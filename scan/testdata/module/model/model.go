@@ -0,0 +1,12 @@
+package model
+
+// User user
+type User struct {
+	// ID id
+	ID int64
+	// Name name
+	Name string
+}
+
+// Role role of user
+type Role string
@@ -0,0 +1,28 @@
+package service
+
+import (
+	m "github.com/pjoc-team/ast/scan/testdata/module/model"
+)
+
+// Service user service
+type Service struct {
+	// Users cached users
+	Users map[int64]*m.User
+	// Owner owner of service
+	Owner *m.User
+	// Admins admins of service
+	Admins []m.User
+	// Roles roles
+	Roles chan m.Role
+}
+
+// Get get user by id
+func (s *Service) Get(id int64) (*m.User, error) {
+	return s.Users[id], nil
+}
+
+// Getter get user
+type Getter interface {
+	// Get get user by id
+	Get(id int64) (*m.User, error)
+}
@@ -30,6 +30,26 @@ type ChanReceiverType <-chan string
 // FuncType func type
 type FuncType func(param1 string, variable ...int) (ret string, err error)
 
+// MapType map type
+type MapType map[string]*StructType
+
+// ArrayType array type
+type ArrayType [4]int
+
+// SliceType slice type
+type SliceType []StructType
+
+// InterfaceType interface type
+type InterfaceType interface {
+	Do(param1 string) error
+}
+
+// AliasType alias type
+type AliasType = StringType
+
+// PointerType pointer type
+type PointerType *StructType
+
 // FuncDeclare func type
 func FuncDeclare(param1 string, variable ...int) (string, error) {
 	return "", nil